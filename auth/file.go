@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tomasmik/winter-is-coming/core"
+)
+
+// fileName is the name of the credentials file in the data directory.
+const fileName = "credentials"
+
+// File is a credential store which survives restarts. Every
+// registered name is appended to a file as a `{name}:{hash}` line.
+type File struct {
+	mem *Memory
+	f   *os.File
+	m   sync.Mutex
+}
+
+// Open opens the credential store kept in the given directory,
+// the directory and the file are created if they don't exist.
+func Open(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, fileName), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &File{
+		mem: NewMemory(),
+		f:   f,
+	}
+	if err := c.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// load reads every registered name in to memory. A torn last
+// line, left by a crash in the middle of a write, is cut off.
+func (c *File) load() error {
+	r := bufio.NewReader(c.f)
+	var offset int64
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) == 0 {
+				return nil
+			}
+			return c.f.Truncate(offset)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Hashes never contain a colon, names might.
+		i := bytes.LastIndexByte(line, ':')
+		if i < 1 {
+			return fmt.Errorf("credentials line %d: expected '{name}:{hash}'", n)
+		}
+		hash := bytes.TrimSpace(line[i+1:])
+		if err := c.mem.add(string(line[:i]), hash); err != nil {
+			return fmt.Errorf("credentials line %d: %w", n, err)
+		}
+		offset += int64(len(line))
+	}
+}
+
+// Register stores the secret of a name nobody has registered.
+func (c *File) Register(name, secret string) error {
+	hash, err := c.mem.hash(name, secret)
+	if err != nil {
+		return err
+	}
+
+	// Names are written under the file lock, so
+	// a name is never written to the file twice.
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.mem.registered(name) {
+		return core.ErrNameRegistered
+	}
+	if _, err := fmt.Fprintf(c.f, "%s:%s\n", name, hash); err != nil {
+		return err
+	}
+	if err := c.f.Sync(); err != nil {
+		return err
+	}
+	return c.mem.add(name, hash)
+}
+
+// Verify checks whether the secret matches the name.
+func (c *File) Verify(name, secret string) error {
+	return c.mem.Verify(name, secret)
+}
+
+// Close closes the file, the store can't be used afterwards.
+func (c *File) Close() error {
+	return c.f.Close()
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tomasmik/winter-is-coming/core"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	cost = bcrypt.MinCost
+}
+
+func TestFile_Reopen(t *testing.T) {
+	type check struct {
+		name    string
+		secret  string
+		wantErr error
+	}
+	tests := []struct {
+		name     string
+		register map[string]string
+		// tail is appended to the file before reopening it.
+		tail   string
+		checks []check
+	}{
+		{
+			name: "empty file, nobody should be registered",
+			checks: []check{
+				{name: "A", secret: "secret", wantErr: core.ErrNotRegistered},
+			},
+		},
+		{
+			name:     "registered names, should be kept",
+			register: map[string]string{"A": "secret", "B:C": "other"},
+			checks: []check{
+				{name: "A", secret: "secret", wantErr: nil},
+				{name: "A", secret: "other", wantErr: core.ErrWrongSecret},
+				{name: "B:C", secret: "other", wantErr: nil},
+			},
+		},
+		{
+			name:     "torn last line, should be cut off",
+			register: map[string]string{"A": "secret"},
+			tail:     "B:$2a$04$",
+			checks: []check{
+				{name: "A", secret: "secret", wantErr: nil},
+				{name: "B", secret: "secret", wantErr: core.ErrNotRegistered},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			c, err := Open(dir)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			for name, secret := range tt.register {
+				if err := c.Register(name, secret); err != nil {
+					t.Fatalf("File.Register() error = %v", err)
+				}
+			}
+			c.Close()
+
+			if tt.tail != "" {
+				f, err := os.OpenFile(filepath.Join(dir, fileName), os.O_WRONLY|os.O_APPEND, 0600)
+				if err != nil {
+					t.Fatal(err)
+				}
+				f.WriteString(tt.tail)
+				f.Close()
+			}
+
+			c, err = Open(dir)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer c.Close()
+			for _, ch := range tt.checks {
+				if err := c.Verify(ch.name, ch.secret); !errors.Is(err, ch.wantErr) {
+					t.Errorf("File.Verify(%s) error = %v, wantErr %v", ch.name, err, ch.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestFile_Register(t *testing.T) {
+	c, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Register("A", "secret"); err != nil {
+		t.Fatalf("File.Register() error = %v", err)
+	}
+	if err := c.Register("A", "other"); !errors.Is(err, core.ErrNameRegistered) {
+		t.Errorf("File.Register() error = %v, wantErr %v", err, core.ErrNameRegistered)
+	}
+}
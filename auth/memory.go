@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"sync"
+
+	"github.com/tomasmik/winter-is-coming/core"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cost is the bcrypt cost secrets are hashed with.
+var cost = bcrypt.DefaultCost
+
+// Memory is a credential store which is kept only
+// in memory, it's lost once the server stops.
+// Secrets are kept as bcrypt hashes.
+type Memory struct {
+	hashes map[string][]byte
+	m      sync.RWMutex
+}
+
+// NewMemory returns an empty in memory credential store.
+func NewMemory() *Memory {
+	return &Memory{
+		hashes: make(map[string][]byte),
+	}
+}
+
+// Register stores the secret of a name nobody has registered.
+func (c *Memory) Register(name, secret string) error {
+	hash, err := c.hash(name, secret)
+	if err != nil {
+		return err
+	}
+	return c.add(name, hash)
+}
+
+// Verify checks whether the secret matches the name.
+func (c *Memory) Verify(name, secret string) error {
+	c.m.RLock()
+	hash, ok := c.hashes[name]
+	c.m.RUnlock()
+	if !ok {
+		return core.ErrNotRegistered
+	}
+
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(secret)); err != nil {
+		return core.ErrWrongSecret
+	}
+	return nil
+}
+
+// hash hashes the secret of a name nobody has registered.
+// Hashing is slow, so it's done without holding the lock.
+func (c *Memory) hash(name, secret string) ([]byte, error) {
+	if c.registered(name) {
+		return nil, core.ErrNameRegistered
+	}
+	return bcrypt.GenerateFromPassword([]byte(secret), cost)
+}
+
+func (c *Memory) registered(name string) bool {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	_, ok := c.hashes[name]
+	return ok
+}
+
+// add stores the hash, unless the name got registered meanwhile.
+func (c *Memory) add(name string, hash []byte) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.hashes[name]; ok {
+		return core.ErrNameRegistered
+	}
+	c.hashes[name] = hash
+	return nil
+}
@@ -0,0 +1,145 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a clock which only moves when it's advanced,
+// it's safe for concurrent use.
+type Fake struct {
+	now     time.Time
+	tickers []*fakeTicker
+	// added is closed and replaced every time a ticker is
+	// created, so BlockUntil can wait for the next one.
+	added chan struct{}
+	m     sync.Mutex
+}
+
+// NewFake returns a fake clock set to the given time.
+func NewFake(now time.Time) *Fake {
+	return &Fake{
+		now:   now,
+		added: make(chan struct{}),
+	}
+}
+
+func (f *Fake) Now() time.Time {
+	f.m.Lock()
+	defer f.m.Unlock()
+	return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+	t := &fakeTicker{
+		f:      f,
+		c:      make(chan time.Time, 1),
+		period: d,
+		next:   f.now.Add(d),
+	}
+	f.tickers = append(f.tickers, t)
+	close(f.added)
+	f.added = make(chan struct{})
+	return t
+}
+
+// Advance moves the clock forward, firing every
+// ticker which is due in the order they are due.
+func (f *Fake) Advance(d time.Duration) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	end := f.now.Add(d)
+	for {
+		t := f.due(end)
+		if t == nil {
+			break
+		}
+		f.now = t.next
+		t.next = t.next.Add(t.period)
+		select {
+		case t.c <- f.now:
+		default:
+		}
+	}
+	f.now = end
+}
+
+// due returns the running ticker which is due first, not later than end.
+func (f *Fake) due(end time.Time) *fakeTicker {
+	var first *fakeTicker
+	for _, t := range f.tickers {
+		if t.next.After(end) {
+			continue
+		}
+		if first == nil || t.next.Before(first.next) {
+			first = t
+		}
+	}
+	return first
+}
+
+// BlockUntil waits until at least n tickers are running.
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.m.Lock()
+		running, added := len(f.tickers), f.added
+		f.m.Unlock()
+		if running >= n {
+			return
+		}
+		<-added
+	}
+}
+
+type fakeTicker struct {
+	f      *Fake
+	c      chan time.Time
+	period time.Duration
+	// next is guarded by the mutex of the clock.
+	next time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("clock: non-positive interval for Reset")
+	}
+
+	t.f.m.Lock()
+	defer t.f.m.Unlock()
+	t.period = d
+	t.next = t.f.now.Add(d)
+	t.f.remove(t)
+	t.f.tickers = append(t.f.tickers, t)
+}
+
+func (t *fakeTicker) Stop() {
+	t.f.m.Lock()
+	defer t.f.m.Unlock()
+	t.f.remove(t)
+}
+
+// remove stops the ticker from firing, the
+// mutex of the clock must be held.
+func (f *Fake) remove(t *fakeTicker) {
+	for i, other := range f.tickers {
+		if other == t {
+			f.tickers = append(f.tickers[:i], f.tickers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2021, 11, 20, 12, 0, 0, 0, time.UTC)
+
+// ticks returns how many ticks are waiting on the ticker.
+func ticks(t Ticker) int {
+	n := 0
+	for {
+		select {
+		case <-t.C():
+			n++
+		default:
+			return n
+		}
+	}
+}
+
+func TestFake_Advance(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance []time.Duration
+		want    int
+	}{
+		{
+			name:    "not due yet, should not tick",
+			advance: []time.Duration{999 * time.Millisecond},
+			want:    0,
+		},
+		{
+			name:    "due, should tick",
+			advance: []time.Duration{500 * time.Millisecond, 500 * time.Millisecond},
+			want:    1,
+		},
+		{
+			name:    "due many times, should drop the ticks nobody took",
+			advance: []time.Duration{5 * time.Second},
+			want:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFake(start)
+			tk := f.NewTicker(time.Second)
+			for _, d := range tt.advance {
+				f.Advance(d)
+			}
+			if got := ticks(tk); got != tt.want {
+				t.Errorf("ticks = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFake_Now(t *testing.T) {
+	f := NewFake(start)
+	f.Advance(90 * time.Second)
+	if got := f.Now(); !got.Equal(start.Add(90 * time.Second)) {
+		t.Errorf("Now() = %v, want %v", got, start.Add(90*time.Second))
+	}
+	if got := f.Since(start); got != 90*time.Second {
+		t.Errorf("Since() = %v, want %v", got, 90*time.Second)
+	}
+}
+
+func TestFakeTicker_Reset(t *testing.T) {
+	f := NewFake(start)
+	tk := f.NewTicker(time.Second)
+	f.Advance(500 * time.Millisecond)
+	tk.Reset(2 * time.Second)
+
+	f.Advance(time.Second)
+	if got := ticks(tk); got != 0 {
+		t.Errorf("ticks before the new period = %d, want 0", got)
+	}
+	f.Advance(time.Second)
+	if got := ticks(tk); got != 1 {
+		t.Errorf("ticks after the new period = %d, want 1", got)
+	}
+}
+
+func TestFakeTicker_Stop(t *testing.T) {
+	f := NewFake(start)
+	tk := f.NewTicker(time.Second)
+	tk.Stop()
+	f.Advance(time.Minute)
+	if got := ticks(tk); got != 0 {
+		t.Errorf("ticks after stopping = %d, want 0", got)
+	}
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	f := NewFake(start)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.BlockUntil(2)
+	}()
+
+	f.NewTicker(time.Second)
+	f.NewTicker(time.Second)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("BlockUntil() didn't return once the tickers were created")
+	}
+}
@@ -3,12 +3,18 @@ package main
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/tomasmik/winter-is-coming/auth"
+	"github.com/tomasmik/winter-is-coming/core"
+	"github.com/tomasmik/winter-is-coming/leaderboard"
 	"github.com/tomasmik/winter-is-coming/server"
 
 	"github.com/fln/pprotect"
@@ -20,6 +26,28 @@ import (
 // nicely with the env dump made in the Makefile.
 var conf struct {
 	Port int `envconfig:"default=8081"`
+	// WSPort is the port browser clients connect to with WebSocket.
+	WSPort int `envconfig:"default=8082"`
+	// AdminAddr is where the admin API is served, it has
+	// no authentication so it's only local by default.
+	AdminAddr string `envconfig:"default=127.0.0.1:8083"`
+	// ReapAfter is how long a game nobody is playing keeps running.
+	ReapAfter time.Duration `envconfig:"default=30s"`
+	// ResumeGrace is how long a dropped player can resume his session.
+	ResumeGrace time.Duration `envconfig:"default=30s"`
+	// DataDir is where the leaderboard and credentials are kept.
+	DataDir string `envconfig:"default=data"`
+	// Auth is one of: off, optional, required.
+	Auth string `envconfig:"default=off"`
+	// Replays enables recording every game in the data directory.
+	Replays bool `envconfig:"default=false"`
+	// Difficulty is the preset of games created without options,
+	// GameOptions are `key=value` options applied on top of it.
+	Difficulty  string `envconfig:"default=normal"`
+	GameOptions string `envconfig:"optional"`
+	// ZombieTypes is the path of the zombie type
+	// catalogue, the built-in one is used if it's empty.
+	ZombieTypes string `envconfig:"optional"`
 }
 
 func main() {
@@ -35,7 +63,61 @@ func main() {
 		logrus.WithError(err).Fatal("failed to start a server")
 	}
 
-	server := server.New(l)
+	authMode, err := core.ParseAuthMode(conf.Auth)
+	if err != nil {
+		logrus.WithError(err).Fatal("parsing environment variables")
+	}
+
+	difficulty, err := core.ParseDifficulty(conf.Difficulty)
+	if err != nil {
+		logrus.WithError(err).Fatal("parsing environment variables")
+	}
+	defaults, err := core.DefaultGameOptions().WithDifficulty(difficulty).Apply(strings.Fields(conf.GameOptions))
+	if err != nil {
+		logrus.WithError(err).Fatal("parsing environment variables")
+	}
+
+	catalogue := core.DefaultCatalogue()
+	if conf.ZombieTypes != "" {
+		data, err := os.ReadFile(conf.ZombieTypes)
+		if err != nil {
+			logrus.WithError(err).Fatal("failed to read the zombie types")
+		}
+		if catalogue, err = core.ParseCatalogue(data); err != nil {
+			logrus.WithError(err).Fatal("parsing the zombie types")
+		}
+	}
+	if _, err := catalogue.Lookup(defaults.Types); err != nil {
+		logrus.WithError(err).Fatal("parsing environment variables")
+	}
+
+	creds, err := auth.Open(conf.DataDir)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to open the credentials")
+	}
+	defer creds.Close()
+
+	board, err := leaderboard.Open(conf.DataDir)
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to open the leaderboard")
+	}
+	defer board.Close()
+
+	var replayDir string
+	if conf.Replays {
+		replayDir = filepath.Join(conf.DataDir, "replays")
+	}
+
+	server := server.New(l, server.Config{
+		ReapAfter:   conf.ReapAfter,
+		ResumeGrace: conf.ResumeGrace,
+		Leaderboard: board,
+		Auth:        authMode,
+		Credentials: creds,
+		ReplayDir:   replayDir,
+		Defaults:    &defaults,
+		Catalogue:   catalogue,
+	})
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go pprotect.CallLoop(func() {
@@ -43,14 +125,37 @@ func main() {
 		server.Run()
 	}, 1*time.Second, panicHandler)
 
+	mux := http.NewServeMux()
+	mux.Handle("/ws", server.WebSocket())
+	ws := serveHTTP(fmt.Sprintf(":%d", conf.WSPort), mux, "websocket")
+	admin := serveHTTP(conf.AdminAddr, server.Admin(), "admin")
+
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
 	<-c
 
+	// Stop accepting browser clients first,
+	// the server disconnects the rest.
+	ws.Close()
+	admin.Close()
 	server.Stop()
 	wg.Wait()
 }
 
+// serveHTTP starts serving the handler in the background.
+func serveHTTP(addr string, h http.Handler, name string) *http.Server {
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: h,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.WithError(err).WithField("server", name).Fatal("failed to start an http server")
+		}
+	}()
+	return srv
+}
+
 func panicHandler(val interface{}, stack []byte) {
 	logrus.WithFields(logrus.Fields{
 		"value": val,
@@ -0,0 +1,75 @@
+// Command replay plays a recorded game back.
+//
+//	replay [-speed 2] [-listen :8084] {file}
+//
+// By default every event is written to stdout with its time and audience.
+// With -listen the tool waits for a single TCP client and streams it the
+// responses the whole game got, just like a spectator would see them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tomasmik/winter-is-coming/replay"
+)
+
+func main() {
+	speed := flag.Float64("speed", 1, "playback speed, 0 plays everything back without waiting")
+	listen := flag.String("listen", "", "address to stream the game to a TCP client from")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] {file}\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *speed < 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	events, err := read(flag.Arg(0))
+	if err != nil {
+		logrus.WithError(err).Fatal("failed to read the replay")
+	}
+
+	if *listen == "" {
+		p := replay.Player{Speed: *speed}
+		if err := p.Play(os.Stdout, events); err != nil {
+			logrus.WithError(err).Fatal("failed to play the replay")
+		}
+		return
+	}
+
+	if err := stream(*listen, replay.Player{Speed: *speed, Public: true}, events); err != nil {
+		logrus.WithError(err).Fatal("failed to stream the replay")
+	}
+}
+
+func read(path string) ([]replay.Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return replay.Read(f)
+}
+
+// stream plays the events back to the first client which connects.
+func stream(addr string, p replay.Player, events []replay.Event) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	logrus.WithField("addr", l.Addr().String()).Info("waiting for a client")
+
+	c, err := l.Accept()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return p.Play(c, events)
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AuthMode describes whether players
+// have to prove that they own their name.
+type AuthMode string
+
+const (
+	// AuthModeOff lets anyone take any free name.
+	AuthModeOff AuthMode = "off"
+	// AuthModeOptional protects only the registered names,
+	// names nobody has registered are free to take.
+	AuthModeOptional AuthMode = "optional"
+	// AuthModeRequired lets only registered players join the server.
+	AuthModeRequired AuthMode = "required"
+)
+
+// Secrets are limited in length, the
+// longest secret bcrypt can hash is 72 bytes.
+const (
+	minSecret = 6
+	maxSecret = 72
+)
+
+var (
+	// ErrNameRegistered is returned when registering a name which is taken.
+	ErrNameRegistered = errors.New("name is already registered")
+	// ErrNotRegistered is returned when verifying a name nobody has registered.
+	ErrNotRegistered = errors.New("name is not registered")
+	// ErrWrongSecret is returned when the secret doesn't match the name.
+	ErrWrongSecret = errors.New("wrong secret")
+
+	errSecretRequired = errors.New("a secret is required to join the server")
+)
+
+// Credentials stores the secrets of registered names.
+// Implementations must be safe for concurrent use.
+type Credentials interface {
+	// Register stores the secret of a name nobody has registered.
+	Register(name, secret string) error
+	// Verify checks whether the secret matches the name.
+	Verify(name, secret string) error
+}
+
+// ParseAuthMode returns the auth mode with the given name.
+func ParseAuthMode(mode string) (AuthMode, error) {
+	switch AuthMode(mode) {
+	case AuthModeOff, AuthModeOptional, AuthModeRequired:
+		return AuthMode(mode), nil
+	}
+	return "", fmt.Errorf("auth mode should be one of: %s, %s, %s", AuthModeOff, AuthModeOptional, AuthModeRequired)
+}
+
+// Authenticate checks whether a player can join
+// the server with the given name and secret.
+func Authenticate(mode AuthMode, creds Credentials, name, secret string) error {
+	switch mode {
+	case AuthModeOptional:
+		err := creds.Verify(name, secret)
+		if secret == "" && errors.Is(err, ErrNotRegistered) {
+			return nil
+		}
+		if secret == "" && err != nil {
+			return errSecretRequired
+		}
+		return err
+	case AuthModeRequired:
+		if secret == "" {
+			return errSecretRequired
+		}
+		return creds.Verify(name, secret)
+	}
+	return nil
+}
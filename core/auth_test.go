@@ -0,0 +1,119 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+// mockCredentials knows a single name with a single secret.
+type mockCredentials struct{}
+
+func (mockCredentials) Register(name, secret string) error {
+	return ErrNameRegistered
+}
+
+func (mockCredentials) Verify(name, secret string) error {
+	if name != "A" {
+		return ErrNotRegistered
+	}
+	if secret != "secret" {
+		return ErrWrongSecret
+	}
+	return nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	type args struct {
+		mode   AuthMode
+		name   string
+		secret string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name:    "auth off, should not error",
+			args:    args{mode: AuthModeOff, name: "A", secret: "wrong"},
+			wantErr: nil,
+		},
+		{
+			name:    "optional auth with unregistered name, should not error",
+			args:    args{mode: AuthModeOptional, name: "B"},
+			wantErr: nil,
+		},
+		{
+			name:    "optional auth with unregistered name and a secret, should error",
+			args:    args{mode: AuthModeOptional, name: "B", secret: "secret"},
+			wantErr: ErrNotRegistered,
+		},
+		{
+			name:    "optional auth with registered name without secret, should error",
+			args:    args{mode: AuthModeOptional, name: "A"},
+			wantErr: errSecretRequired,
+		},
+		{
+			name:    "optional auth with registered name, should not error",
+			args:    args{mode: AuthModeOptional, name: "A", secret: "secret"},
+			wantErr: nil,
+		},
+		{
+			name:    "required auth without secret, should error",
+			args:    args{mode: AuthModeRequired, name: "B"},
+			wantErr: errSecretRequired,
+		},
+		{
+			name:    "required auth with wrong secret, should error",
+			args:    args{mode: AuthModeRequired, name: "A", secret: "wrong"},
+			wantErr: ErrWrongSecret,
+		},
+		{
+			name:    "required auth with registered name, should not error",
+			args:    args{mode: AuthModeRequired, name: "A", secret: "secret"},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authenticate(tt.args.mode, mockCredentials{}, tt.args.name, tt.args.secret)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authenticate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseAuthMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		want    AuthMode
+		wantErr bool
+	}{
+		{
+			name:    "known mode, should not error",
+			mode:    "required",
+			want:    AuthModeRequired,
+			wantErr: false,
+		},
+		{
+			name:    "unknown mode, should error",
+			mode:    "sometimes",
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAuthMode(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseAuthMode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseAuthMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,16 +7,69 @@ import (
 	"strings"
 )
 
+// CommandHello is returned when a clients
+// message is parsed as a protocol handshake.
+type CommandHello struct {
+	Version      int
+	Capabilities []Capability
+}
+
 // CommandJoinServer is returned when a clients
 // message is parsed as a request to join a server.
 type CommandJoinServer struct {
 	Name string
+	// Secret is set only if the client gave it,
+	// it proves that the player owns the name.
+	Secret string
+}
+
+// CommandRegister is returned when a clients message
+// is parsed as a request to register a player name.
+type CommandRegister struct {
+	Name   string
+	Secret string
+}
+
+// CommandResume is returned when a clients message is
+// parsed as a request to resume a dropped session.
+type CommandResume struct {
+	Token string
 }
 
 // CommandJoinGame is returned when a clients
 // message is parsed as a request to join a game.
 type CommandJoinGame struct {
 	GameName string
+	// Options are the `key=value` options the client gave, they
+	// are applied on top of the server defaults when the game
+	// gets created. They are validated while parsing.
+	Options []string
+}
+
+// CommandLeaveGame is returned when a clients
+// message is parsed as a request to leave a game.
+type CommandLeaveGame struct{}
+
+// CommandListGames is returned when a clients
+// message is parsed as a request to list active games.
+type CommandListGames struct{}
+
+// CommandGameInfo is returned when a clients message
+// is parsed as a request for the state of a single game.
+type CommandGameInfo struct {
+	GameName string
+}
+
+// CommandSpectate is returned when a clients message is
+// parsed as a request to watch a game without playing it.
+type CommandSpectate struct {
+	GameName string
+}
+
+// CommandLeaderboard is returned when a clients message
+// is parsed as a request for the best players.
+type CommandLeaderboard struct {
+	N int
 }
 
 // CommandShoot is returned when a clients
@@ -31,17 +84,69 @@ type CommandShoot struct {
 type CommandType string
 
 const (
+	// CommandTypeHello is expected as the first message of
+	// a connection when the client wants to negotiate the protocol.
+	CommandTypeHello CommandType = "HELLO"
 	// CommandTypeJoinServer is expected when the client
 	// connects and wants to join the server as a player.
 	CommandTypeJoinServer CommandType = "JOINSERVER"
+	// CommandTypeRegister is expected when the client wants
+	// to protect a player name with a secret.
+	CommandTypeRegister CommandType = "REGISTER"
+	// CommandTypeResume is expected when the client reconnects
+	// and wants to continue the session of a dropped connection.
+	CommandTypeResume CommandType = "RESUME"
 	// CommandTypeJoinGame is expected when the client
 	// is connected as a plyer and wants to join a game.
 	CommandTypeJoinGame CommandType = "JOINGAME"
+	// CommandTypeLeaveGame is expected when the client
+	// is in a game and wants to leave it.
+	CommandTypeLeaveGame CommandType = "LEAVEGAME"
+	// CommandTypeListGames is expected when the client
+	// wants to see which games are running.
+	CommandTypeListGames CommandType = "LISTGAMES"
+	// CommandTypeGameInfo is expected when the client
+	// wants to see the state of a single game.
+	CommandTypeGameInfo CommandType = "GAMEINFO"
+	// CommandTypeSpectate is expected when the client wants
+	// to watch a game without joining it as a player.
+	CommandTypeSpectate CommandType = "SPECTATE"
+	// CommandTypeLeaderboard is expected when the client
+	// wants to see the best players of the server.
+	CommandTypeLeaderboard CommandType = "LEADERBOARD"
 	// CommandTypeShoot is expected to be received from the client
 	// when he has joined a game and is trying to shoot a zombie.
 	CommandTypeShoot CommandType = "SHOOT"
 )
 
+func ParseCommandHello(received string) (*CommandHello, error) {
+	err := fmt.Errorf("expected format for hello command is '%s {version} {capabilities...}'", CommandTypeHello)
+
+	parts := strings.Split(received, " ")
+	if len(parts) < 2 {
+		return nil, err
+	}
+	if CommandType(parts[0]) != CommandTypeHello {
+		return nil, err
+	}
+	version, verr := strconv.Atoi(parts[1])
+	if verr != nil || version < ProtocolVersionLegacy {
+		return nil, errors.New("could not parse protocol version")
+	}
+
+	caps := make([]Capability, 0, len(parts)-2)
+	for _, c := range parts[2:] {
+		if c == "" {
+			continue
+		}
+		caps = append(caps, Capability(c))
+	}
+	return &CommandHello{
+		Version:      version,
+		Capabilities: caps,
+	}, nil
+}
+
 func ParseCommandShoot(received string) (*CommandShoot, error) {
 	parts := strings.Split(received, " ")
 	if len(parts) != 3 {
@@ -65,10 +170,10 @@ func ParseCommandShoot(received string) (*CommandShoot, error) {
 }
 
 func ParseCommandJoinGame(received string) (*CommandJoinGame, error) {
-	err := fmt.Errorf("expected format for join game command is '%s {name}'", CommandTypeJoinGame)
+	err := fmt.Errorf("expected format for join game command is '%s {name} {options...}'", CommandTypeJoinGame)
 
 	parts := strings.Split(received, " ")
-	if len(parts) != 2 {
+	if len(parts) < 2 {
 		return nil, err
 	}
 	if CommandType(parts[0]) != CommandTypeJoinGame {
@@ -77,38 +182,169 @@ func ParseCommandJoinGame(received string) (*CommandJoinGame, error) {
 	if parts[1] == "" {
 		return nil, err
 	}
-	return &CommandJoinGame{
+
+	cmd := &CommandJoinGame{
+		GameName: parts[1],
+	}
+	if len(parts) > 2 {
+		if _, err := ParseGameOptions(parts[2:]); err != nil {
+			return nil, err
+		}
+		cmd.Options = parts[2:]
+	}
+	return cmd, nil
+}
+
+func ParseCommandLeaveGame(received string) (*CommandLeaveGame, error) {
+	if CommandType(received) != CommandTypeLeaveGame {
+		return nil, fmt.Errorf("expected format for leave game command is '%s'", CommandTypeLeaveGame)
+	}
+	return &CommandLeaveGame{}, nil
+}
+
+func ParseCommandListGames(received string) (*CommandListGames, error) {
+	if CommandType(received) != CommandTypeListGames {
+		return nil, fmt.Errorf("expected format for list games command is '%s'", CommandTypeListGames)
+	}
+	return &CommandListGames{}, nil
+}
+
+func ParseCommandGameInfo(received string) (*CommandGameInfo, error) {
+	err := fmt.Errorf("expected format for game info command is '%s {name}'", CommandTypeGameInfo)
+
+	parts := strings.Split(received, " ")
+	if len(parts) != 2 {
+		return nil, err
+	}
+	if CommandType(parts[0]) != CommandTypeGameInfo {
+		return nil, err
+	}
+	if parts[1] == "" {
+		return nil, err
+	}
+	return &CommandGameInfo{
 		GameName: parts[1],
 	}, nil
 }
 
-func ParseCommandJoinServer(received string) (*CommandJoinServer, error) {
-	err := fmt.Errorf("expected format for join command is '%s {name}'", CommandTypeJoinServer)
+func ParseCommandSpectate(received string) (*CommandSpectate, error) {
+	err := fmt.Errorf("expected format for spectate command is '%s {name}'", CommandTypeSpectate)
 
 	parts := strings.Split(received, " ")
 	if len(parts) != 2 {
 		return nil, err
 	}
+	if CommandType(parts[0]) != CommandTypeSpectate {
+		return nil, err
+	}
+	if parts[1] == "" {
+		return nil, err
+	}
+	return &CommandSpectate{
+		GameName: parts[1],
+	}, nil
+}
+
+func ParseCommandLeaderboard(received string) (*CommandLeaderboard, error) {
+	err := fmt.Errorf("expected format for leaderboard command is '%s [n]'", CommandTypeLeaderboard)
+
+	parts := strings.Split(received, " ")
+	if len(parts) > 2 {
+		return nil, err
+	}
+	if CommandType(parts[0]) != CommandTypeLeaderboard {
+		return nil, err
+	}
+	if len(parts) == 1 {
+		return &CommandLeaderboard{
+			N: DefaultLeaderboardSize,
+		}, nil
+	}
+	n, nerr := strconv.Atoi(parts[1])
+	if nerr != nil || n < 1 || n > maxLeaderboardSize {
+		return nil, fmt.Errorf("leaderboard size should be a number between 1 and %d", maxLeaderboardSize)
+	}
+	return &CommandLeaderboard{
+		N: n,
+	}, nil
+}
+
+func ParseCommandJoinServer(received string) (*CommandJoinServer, error) {
+	err := fmt.Errorf("expected format for join command is '%s {name} {secret?}'", CommandTypeJoinServer)
+
+	parts := strings.Split(received, " ")
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, err
+	}
 	if CommandType(parts[0]) != CommandTypeJoinServer {
 		return nil, err
 	}
 	if parts[1] == "" {
 		return nil, err
 	}
-	return &CommandJoinServer{
+	cmd := &CommandJoinServer{
 		Name: parts[1],
+	}
+	if len(parts) == 3 {
+		if parts[2] == "" {
+			return nil, err
+		}
+		cmd.Secret = parts[2]
+	}
+	return cmd, nil
+}
+
+func ParseCommandRegister(received string) (*CommandRegister, error) {
+	err := fmt.Errorf("expected format for register command is '%s {name} {secret}'", CommandTypeRegister)
+
+	parts := strings.Split(received, " ")
+	if len(parts) != 3 {
+		return nil, err
+	}
+	if CommandType(parts[0]) != CommandTypeRegister {
+		return nil, err
+	}
+	if parts[1] == "" {
+		return nil, err
+	}
+	if len(parts[2]) < minSecret || len(parts[2]) > maxSecret {
+		return nil, fmt.Errorf("secret should be between %d and %d characters long", minSecret, maxSecret)
+	}
+	return &CommandRegister{
+		Name:   parts[1],
+		Secret: parts[2],
+	}, nil
+}
+
+func ParseCommandResume(received string) (*CommandResume, error) {
+	err := fmt.Errorf("expected format for resume command is '%s {token}'", CommandTypeResume)
+
+	parts := strings.Split(received, " ")
+	if len(parts) != 2 {
+		return nil, err
+	}
+	if CommandType(parts[0]) != CommandTypeResume {
+		return nil, err
+	}
+	if parts[1] == "" {
+		return nil, err
+	}
+	return &CommandResume{
+		Token: parts[1],
 	}, nil
 }
 
 func ParseCommandType(received string) (CommandType, error) {
 	parts := strings.Split(received, " ")
-	if len(parts) <= 1 {
+	if parts[0] == "" {
 		return "", errors.New("a command should consist of type+arguments")
 	}
 
 	cmd := CommandType(parts[0])
 	switch cmd {
-	case CommandTypeShoot, CommandTypeJoinServer, CommandTypeJoinGame:
+	case CommandTypeHello, CommandTypeShoot, CommandTypeJoinServer, CommandTypeJoinGame, CommandTypeLeaveGame,
+		CommandTypeListGames, CommandTypeGameInfo, CommandTypeLeaderboard, CommandTypeResume,
+		CommandTypeRegister, CommandTypeSpectate:
 	default:
 		return "", fmt.Errorf("%s is not a command server understands", cmd)
 	}
@@ -5,6 +5,77 @@ import (
 	"testing"
 )
 
+func TestParseCommandHello(t *testing.T) {
+	type args struct {
+		received string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *CommandHello
+		wantErr bool
+	}{
+		{
+			name: "received wrong command, should error",
+			args: args{
+				received: "random text",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received non integer version, should error",
+			args: args{
+				received: "HELLO x JSON",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received version lower than legacy, should error",
+			args: args{
+				received: "HELLO 0",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command HELLO without capabilities, should not error",
+			args: args{
+				received: "HELLO 2",
+			},
+			want: &CommandHello{
+				Version:      2,
+				Capabilities: []Capability{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "received command HELLO with capabilities, should not error",
+			args: args{
+				received: "HELLO 2 JSON MOCK",
+			},
+			want: &CommandHello{
+				Version:      2,
+				Capabilities: []Capability{CapabilityJSON, "MOCK"},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandHello(tt.args.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCommandHello() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommandHello() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseCommandShoot(t *testing.T) {
 	type args struct {
 		received string
@@ -101,6 +172,25 @@ func TestParseCommandJoinGame(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "received command JOINGAME with invalid option, should error",
+			args: args{
+				received: "JOINGAME mock misses",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command JOINGAME with options, should not error",
+			args: args{
+				received: "JOINGAME mock misses=all",
+			},
+			want: &CommandJoinGame{
+				GameName: "mock",
+				Options:  []string{"misses=all"},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -116,6 +206,318 @@ func TestParseCommandJoinGame(t *testing.T) {
 	}
 }
 
+func TestParseCommandLeaveGame(t *testing.T) {
+	type args struct {
+		received string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *CommandLeaveGame
+		wantErr bool
+	}{
+		{
+			name: "received wrong command, should error",
+			args: args{
+				received: "random text",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command with arguments, should error",
+			args: args{
+				received: "LEAVEGAME mock",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command LEAVEGAME, should not error",
+			args: args{
+				received: "LEAVEGAME",
+			},
+			want:    &CommandLeaveGame{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandLeaveGame(tt.args.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCommandLeaveGame() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommandLeaveGame() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommandListGames(t *testing.T) {
+	type args struct {
+		received string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *CommandListGames
+		wantErr bool
+	}{
+		{
+			name: "received command with arguments, should error",
+			args: args{
+				received: "LISTGAMES mock",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command LISTGAMES, should not error",
+			args: args{
+				received: "LISTGAMES",
+			},
+			want:    &CommandListGames{},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandListGames(tt.args.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCommandListGames() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommandListGames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommandGameInfo(t *testing.T) {
+	type args struct {
+		received string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *CommandGameInfo
+		wantErr bool
+	}{
+		{
+			name: "received wrong command, should error",
+			args: args{
+				received: "random text",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "missing parts, should error",
+			args: args{
+				received: "GAMEINFO ",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command GAMEINFO with 1 string arg, should not error",
+			args: args{
+				received: "GAMEINFO mock",
+			},
+			want: &CommandGameInfo{
+				GameName: "mock",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandGameInfo(tt.args.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCommandGameInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommandGameInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommandSpectate(t *testing.T) {
+	type args struct {
+		received string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *CommandSpectate
+		wantErr bool
+	}{
+		{
+			name: "received wrong command, should error",
+			args: args{
+				received: "GAMEINFO mock",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "missing parts, should error",
+			args: args{
+				received: "SPECTATE ",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "too many parts, should error",
+			args: args{
+				received: "SPECTATE mock other",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command SPECTATE with 1 string arg, should not error",
+			args: args{
+				received: "SPECTATE mock",
+			},
+			want: &CommandSpectate{
+				GameName: "mock",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandSpectate(tt.args.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCommandSpectate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommandSpectate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommandLeaderboard(t *testing.T) {
+	type args struct {
+		received string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *CommandLeaderboard
+		wantErr bool
+	}{
+		{
+			name: "received wrong command, should error",
+			args: args{
+				received: "random text",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid size, should error",
+			args: args{
+				received: "LEADERBOARD 0",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command LEADERBOARD without args, should return default size",
+			args: args{
+				received: "LEADERBOARD",
+			},
+			want: &CommandLeaderboard{
+				N: DefaultLeaderboardSize,
+			},
+			wantErr: false,
+		},
+		{
+			name: "received command LEADERBOARD with 1 int arg, should not error",
+			args: args{
+				received: "LEADERBOARD 5",
+			},
+			want: &CommandLeaderboard{
+				N: 5,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandLeaderboard(tt.args.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCommandLeaderboard() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommandLeaderboard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommandResume(t *testing.T) {
+	type args struct {
+		received string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *CommandResume
+		wantErr bool
+	}{
+		{
+			name: "received wrong command, should error",
+			args: args{
+				received: "random text",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "missing parts, should error",
+			args: args{
+				received: "RESUME",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command RESUME with 1 string arg, should not error",
+			args: args{
+				received: "RESUME abc",
+			},
+			want: &CommandResume{
+				Token: "abc",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandResume(tt.args.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCommandResume() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommandResume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseCommandJoinServer(t *testing.T) {
 	type args struct {
 		received string
@@ -152,6 +554,25 @@ func TestParseCommandJoinServer(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "received command JOINSERVER with a secret, should not error",
+			args: args{
+				received: "JOINSERVER mock secret",
+			},
+			want: &CommandJoinServer{
+				Name:   "mock",
+				Secret: "secret",
+			},
+			wantErr: false,
+		},
+		{
+			name: "too many parts, should error",
+			args: args{
+				received: "JOINSERVER mock secret more",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -167,6 +588,66 @@ func TestParseCommandJoinServer(t *testing.T) {
 	}
 }
 
+func TestParseCommandRegister(t *testing.T) {
+	type args struct {
+		received string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *CommandRegister
+		wantErr bool
+	}{
+		{
+			name: "received wrong command, should error",
+			args: args{
+				received: "random text",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "missing secret, should error",
+			args: args{
+				received: "REGISTER mock",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "short secret, should error",
+			args: args{
+				received: "REGISTER mock abc",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "received command REGISTER with name and secret, should not error",
+			args: args{
+				received: "REGISTER mock secret",
+			},
+			want: &CommandRegister{
+				Name:   "mock",
+				Secret: "secret",
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandRegister(tt.args.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCommandRegister() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommandRegister() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseCommandType(t *testing.T) {
 	type args struct {
 		received string
@@ -193,6 +674,14 @@ func TestParseCommandType(t *testing.T) {
 			want:    "",
 			wantErr: true,
 		},
+		{
+			name: "command HELLO, should not error",
+			args: args{
+				received: "HELLO 2",
+			},
+			want:    CommandTypeHello,
+			wantErr: false,
+		},
 		{
 			name: "command SHOOT, should not error",
 			args: args{
@@ -217,6 +706,30 @@ func TestParseCommandType(t *testing.T) {
 			want:    CommandTypeJoinGame,
 			wantErr: false,
 		},
+		{
+			name: "command LEAVEGAME without arguments, should not error",
+			args: args{
+				received: "LEAVEGAME",
+			},
+			want:    CommandTypeLeaveGame,
+			wantErr: false,
+		},
+		{
+			name: "command LISTGAMES without arguments, should not error",
+			args: args{
+				received: "LISTGAMES",
+			},
+			want:    CommandTypeListGames,
+			wantErr: false,
+		},
+		{
+			name: "command SPECTATE, should not error",
+			args: args{
+				received: "SPECTATE x",
+			},
+			want:    CommandTypeSpectate,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Difficulty is a type which describes a preset of
+// the board size, zombie count, toughness and speed.
+type Difficulty string
+
+const (
+	DifficultyEasy      Difficulty = "easy"
+	DifficultyNormal    Difficulty = "normal"
+	DifficultyHard      Difficulty = "hard"
+	DifficultyNightmare Difficulty = "nightmare"
+)
+
+// defaultTick is how often the zombies
+// walk in the normal difficulty.
+const defaultTick = 4 * time.Second
+
+// preset are the options a difficulty decides on.
+type preset struct {
+	width     int
+	height    int
+	zombies   int
+	hitPoints int
+	tick      time.Duration
+}
+
+var presets = map[Difficulty]preset{
+	DifficultyEasy: {
+		width:     15,
+		height:    20,
+		zombies:   1,
+		hitPoints: 2,
+		tick:      6 * time.Second,
+	},
+	DifficultyNormal: {
+		width:     defaultWidth,
+		height:    defaultHeight,
+		zombies:   defaultZombies,
+		hitPoints: defaultHitPoints,
+		tick:      defaultTick,
+	},
+	DifficultyHard: {
+		width:     8,
+		height:    30,
+		zombies:   3,
+		hitPoints: 4,
+		tick:      3 * time.Second,
+	},
+	DifficultyNightmare: {
+		width:     6,
+		height:    40,
+		zombies:   6,
+		hitPoints: 5,
+		tick:      2 * time.Second,
+	},
+}
+
+// ParseDifficulty returns the difficulty with the given name.
+func ParseDifficulty(s string) (Difficulty, error) {
+	d := Difficulty(s)
+	if _, ok := presets[d]; !ok {
+		return "", fmt.Errorf("difficulty should be one of: %s, %s, %s, %s",
+			DifficultyEasy, DifficultyNormal, DifficultyHard, DifficultyNightmare)
+	}
+	return d, nil
+}
+
+// WithDifficulty returns the options with the preset of the difficulty,
+// the game mode, misses, seed, movement and zombie types are kept.
+func (o GameOptions) WithDifficulty(d Difficulty) GameOptions {
+	p := presets[d]
+	o.Difficulty = d
+	o.Width = p.width
+	o.Height = p.height
+	o.Zombies = p.zombies
+	o.HitPoints = p.hitPoints
+	o.Tick = p.tick
+	return o
+}
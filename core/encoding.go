@@ -0,0 +1,215 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Encoding is a type which describes how commands
+// and responses are serialized on the wire.
+type Encoding string
+
+const (
+	// EncodingText is the default encoding, commands
+	// and responses are space separated plain-text lines.
+	EncodingText Encoding = "TEXT"
+	// EncodingJSON encodes every command and
+	// response as a single line JSON object.
+	EncodingJSON Encoding = "JSON"
+)
+
+// commandArgs describes in which order the JSON command
+// fields are placed when converting them to plain-text.
+var commandArgs = map[CommandType][]string{
+	CommandTypeHello:       {"version", "capabilities"},
+	CommandTypeJoinServer:  {"name", "secret"},
+	CommandTypeRegister:    {"name", "secret"},
+	CommandTypeResume:      {"token"},
+	CommandTypeJoinGame:    {"game", "options"},
+	CommandTypeGameInfo:    {"game"},
+	CommandTypeSpectate:    {"game"},
+	CommandTypeLeaderboard: {"n"},
+	CommandTypeShoot:       {"x", "y"},
+}
+
+// requestIDPrefix marks the request ID of a plain-text command.
+const requestIDPrefix = "#"
+
+// maxRequestID limits how long a request ID can be.
+const maxRequestID = 64
+
+// ErrRequestID is returned when the request ID of a command is malformed.
+var ErrRequestID = fmt.Errorf("request ID should be 1 to %d characters without spaces", maxRequestID)
+
+// ErrLegacySkipped is returned when a response isn't
+// sent to the clients of the legacy protocol version.
+var ErrLegacySkipped = errors.New("response isn't sent to legacy clients")
+
+// DetectEncoding returns the encoding a received line is in.
+func DetectEncoding(received string) Encoding {
+	if strings.HasPrefix(strings.TrimSpace(received), "{") {
+		return EncodingJSON
+	}
+	return EncodingText
+}
+
+// DecodeCommand converts a received line in to the plain-text
+// command, so it can be parsed by the Parse* functions
+// no matter which encoding the client has picked.
+// The `id` field of a JSON command is converted to
+// the request ID prefix, see SplitRequestID.
+func DecodeCommand(enc Encoding, received string) (string, error) {
+	if enc != EncodingJSON {
+		return received, nil
+	}
+
+	d := json.NewDecoder(strings.NewReader(received))
+	d.UseNumber()
+
+	var fields map[string]interface{}
+	if err := d.Decode(&fields); err != nil {
+		return "", errors.New("expected a JSON object")
+	}
+
+	cmd, ok := fields["cmd"].(string)
+	if !ok || cmd == "" {
+		return "", errors.New("a JSON command should have a 'cmd' field")
+	}
+
+	parts := []string{cmd}
+	if val, ok := fields["id"]; ok {
+		id, err := jsonArg(val)
+		if err != nil || strings.Contains(id, " ") {
+			return "", ErrRequestID
+		}
+		parts = []string{requestIDPrefix + id, cmd}
+	}
+	for _, key := range commandArgs[CommandType(cmd)] {
+		val, ok := fields[key]
+		if !ok {
+			break
+		}
+		arg, err := jsonArg(val)
+		if err != nil {
+			return "", fmt.Errorf("field '%s': %w", key, err)
+		}
+		if arg != "" {
+			parts = append(parts, arg)
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// jsonArg converts a single JSON value to a plain-text argument.
+// Lists are converted to multiple space separated arguments
+// and objects to sorted `key=value` arguments.
+func jsonArg(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		if v == "" || strings.ContainsAny(v, " \t\r\n") {
+			return "", errors.New("should be a non empty string without spaces")
+		}
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return fmt.Sprint(v), nil
+	case []interface{}:
+		args := make([]string, 0, len(v))
+		for _, item := range v {
+			if _, ok := item.([]interface{}); ok {
+				return "", errors.New("nested lists are not supported")
+			}
+			arg, err := jsonArg(item)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, arg)
+		}
+		return strings.Join(args, " "), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		args := make([]string, 0, len(v))
+		for _, key := range keys {
+			switch v[key].(type) {
+			case []interface{}, map[string]interface{}:
+				return "", errors.New("nested objects are not supported")
+			}
+			arg, err := jsonArg(v[key])
+			if err != nil {
+				return "", err
+			}
+			args = append(args, key+"="+arg)
+		}
+		return strings.Join(args, " "), nil
+	default:
+		return "", errors.New("unsupported value")
+	}
+}
+
+// SplitRequestID splits a plain-text command in to its request
+// ID and the command, the ID is given as `#{id} {command}`.
+// An empty ID is returned if the command isn't tagged.
+func SplitRequestID(received string) (string, string, error) {
+	if !strings.HasPrefix(received, requestIDPrefix) {
+		return "", received, nil
+	}
+
+	parts := strings.SplitN(received, " ", 2)
+	id := strings.TrimPrefix(parts[0], requestIDPrefix)
+	if id == "" || len(id) > maxRequestID {
+		return "", "", ErrRequestID
+	}
+	if len(parts) == 1 {
+		return id, "", nil
+	}
+	return id, strings.TrimSpace(parts[1]), nil
+}
+
+// EncodeResponse converts a response in to a single line using the
+// given encoding and protocol version. Plain-text responses are sent
+// to the legacy clients in their legacy format, ErrLegacySkipped is
+// returned for the ones they aren't sent at all. A reply is tagged
+// with its request ID. Returned line has no line ending.
+func EncodeResponse(enc Encoding, version int, resp Response) (string, error) {
+	var id string
+	if r, ok := resp.(*Reply); ok {
+		resp, id = r.Response, r.Request.ID
+	}
+
+	if enc != EncodingJSON {
+		line := resp.String()
+		if l, ok := resp.(legacyResponse); ok && version < ProtocolVersion {
+			line = l.LegacyString()
+			if line == "" {
+				return "", ErrLegacySkipped
+			}
+		}
+		if id != "" {
+			line = requestIDPrefix + id + " " + line
+		}
+		return line, nil
+	}
+
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return "", err
+	}
+	if id != "" {
+		// Every response is a JSON object, the ID is put first.
+		tag, err := json.Marshal(id)
+		if err != nil {
+			return "", err
+		}
+		b = append([]byte(`{"id":`+string(tag)+`,`), b[1:]...)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,430 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	type args struct {
+		received string
+	}
+	tests := []struct {
+		name string
+		args args
+		want Encoding
+	}{
+		{
+			name: "plain text command, should return text",
+			args: args{
+				received: "SHOOT 1 1",
+			},
+			want: EncodingText,
+		},
+		{
+			name: "JSON object, should return JSON",
+			args: args{
+				received: ` {"cmd":"SHOOT","x":1,"y":1}`,
+			},
+			want: EncodingJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectEncoding(tt.args.received); got != tt.want {
+				t.Errorf("DetectEncoding() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCommand(t *testing.T) {
+	type args struct {
+		enc      Encoding
+		received string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "text encoding, should return the received line",
+			args: args{
+				enc:      EncodingText,
+				received: "SHOOT 1 2",
+			},
+			want:    "SHOOT 1 2",
+			wantErr: false,
+		},
+		{
+			name: "invalid JSON, should error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"cmd":`,
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "missing cmd field, should error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"x":1,"y":2}`,
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "string argument with spaces, should error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"cmd":"JOINSERVER","name":"a b"}`,
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "JSON shoot command, should not error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"cmd":"SHOOT","x":3,"y":7}`,
+			},
+			want:    "SHOOT 3 7",
+			wantErr: false,
+		},
+		{
+			name: "JSON hello command with capabilities, should not error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"cmd":"HELLO","version":2,"capabilities":["JSON"]}`,
+			},
+			want:    "HELLO 2 JSON",
+			wantErr: false,
+		},
+		{
+			name: "JSON hello command with empty capabilities, should not error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"cmd":"HELLO","version":2,"capabilities":[]}`,
+			},
+			want:    "HELLO 2",
+			wantErr: false,
+		},
+		{
+			name: "JSON join game command, should not error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"game":"mock","cmd":"JOINGAME"}`,
+			},
+			want:    "JOINGAME mock",
+			wantErr: false,
+		},
+		{
+			name: "JSON join game command with options, should not error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"cmd":"JOINGAME","game":"mock","options":{"misses":"all","b":1}}`,
+			},
+			want:    "JOINGAME mock b=1 misses=all",
+			wantErr: false,
+		},
+		{
+			name: "JSON join game command with nested options, should error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"cmd":"JOINGAME","game":"mock","options":{"misses":["all"]}}`,
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "JSON command with an ID, should not error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"id":"a1","cmd":"SHOOT","x":3,"y":7}`,
+			},
+			want:    "#a1 SHOOT 3 7",
+			wantErr: false,
+		},
+		{
+			name: "JSON command with a numeric ID, should not error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"id":7,"cmd":"JOINGAME","game":"mock"}`,
+			},
+			want:    "#7 JOINGAME mock",
+			wantErr: false,
+		},
+		{
+			name: "JSON command with an ID with spaces, should error",
+			args: args{
+				enc:      EncodingJSON,
+				received: `{"id":"a b","cmd":"JOINGAME","game":"mock"}`,
+			},
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCommand(tt.args.enc, tt.args.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeCommand() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("DecodeCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		received string
+		wantID   string
+		wantCmd  string
+		wantErr  bool
+	}{
+		{
+			name:     "untagged command, should return no ID",
+			received: "SHOOT 1 2",
+			wantID:   "",
+			wantCmd:  "SHOOT 1 2",
+		},
+		{
+			name:     "tagged command, should return the ID",
+			received: "#a1 SHOOT 1 2",
+			wantID:   "a1",
+			wantCmd:  "SHOOT 1 2",
+		},
+		{
+			name:     "tag without a command, should return an empty command",
+			received: "#a1",
+			wantID:   "a1",
+			wantCmd:  "",
+		},
+		{
+			name:     "empty tag, should error",
+			received: "# SHOOT 1 2",
+			wantErr:  true,
+		},
+		{
+			name:     "too long tag, should error",
+			received: "#" + strings.Repeat("a", maxRequestID+1) + " SHOOT 1 2",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, cmd, err := SplitRequestID(tt.received)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SplitRequestID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if id != tt.wantID || cmd != tt.wantCmd {
+				t.Errorf("SplitRequestID() = %v, %v, want %v, %v", id, cmd, tt.wantID, tt.wantCmd)
+			}
+		})
+	}
+}
+
+func TestEncodeResponse(t *testing.T) {
+	type args struct {
+		enc     Encoding
+		version int
+		resp    Response
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name: "text encoding ResponseWalk",
+			args: args{
+				enc:  EncodingText,
+				resp: NewResponseWalk("night-king", "walker", 1, 2),
+			},
+			want: "WALK night-king 1 2 walker",
+		},
+		{
+			name: "text encoding ResponseWalk for a legacy client",
+			args: args{
+				enc:     EncodingText,
+				version: ProtocolVersionLegacy,
+				resp:    NewResponseWalk("night-king", "walker", 1, 2),
+			},
+			want: "WALK night-king 1 2",
+		},
+		{
+			name: "text encoding ResponseBoom for a legacy client",
+			args: args{
+				enc:     EncodingText,
+				version: ProtocolVersionLegacy,
+				resp:    NewResponseBoom("mock", "night-king", "walker", 1),
+			},
+			want: "BOOM mock 1 night-king",
+		},
+		{
+			name: "text encoding ResponseJoined for a legacy client",
+			args: args{
+				enc:     EncodingText,
+				version: ProtocolVersionLegacy,
+				resp: NewResponseJoined("mock", []string{"a", "b"}, []ZombieInfo{
+					{Name: "night-king", X: 1, Y: 2},
+					{Name: "walker", X: 0, Y: 3},
+				}),
+			},
+			want: "JOINED mock a b night-king 1 2 walker 0 3",
+		},
+		{
+			name: "text encoding ResponseGames for a legacy client",
+			args: args{
+				enc:     EncodingText,
+				version: ProtocolVersionLegacy,
+				resp:    NewResponseGames([]GameInfo{{Name: "a"}, {Name: "b"}}),
+			},
+			want: "GAMES 2 a b",
+		},
+		{
+			name: "text encoding ResponseLeaderboard for a legacy client",
+			args: args{
+				enc:     EncodingText,
+				version: ProtocolVersionLegacy,
+				resp:    NewResponseLeaderboard([]Standing{{Player: "a", Wins: 2}, {Player: "b"}}),
+			},
+			want: "LEADERBOARD 2 a b",
+		},
+		{
+			name: "text encoding ResponseFinish of the waves game mode for a legacy client",
+			args: args{
+				enc:     EncodingText,
+				version: ProtocolVersionLegacy,
+				resp:    NewResponseFinishWaves(3),
+			},
+			want: "FINISH LOST",
+		},
+		{
+			name: "text encoding ResponseFinish of the winner for a legacy client",
+			args: args{
+				enc:     EncodingText,
+				version: ProtocolVersionLegacy,
+				resp:    NewResponseFinishWinner("mock"),
+			},
+			want: "FINISH WON",
+		},
+		{
+			name: "text encoding ResponseScore for a legacy client",
+			args: args{
+				enc:     EncodingText,
+				version: ProtocolVersionLegacy,
+				resp:    NewResponseScore(nil),
+			},
+			wantErr: ErrLegacySkipped,
+		},
+		{
+			name: "JSON encoding ResponseScore for a legacy client",
+			args: args{
+				enc:     EncodingJSON,
+				version: ProtocolVersionLegacy,
+				resp:    NewResponseScore(nil),
+			},
+			want: `{"type":"SCORE","players":[]}`,
+		},
+		{
+			name: "text encoding reply",
+			args: args{
+				enc:  EncodingText,
+				resp: Request{ID: "a1"}.Reply(NewResponseError(errors.New("a"))),
+			},
+			want: "#a1 ERROR a",
+		},
+		{
+			name: "text encoding reply without an ID",
+			args: args{
+				enc:  EncodingText,
+				resp: Request{}.Reply(NewResponseError(errors.New("a"))),
+			},
+			want: "ERROR a",
+		},
+		{
+			name: "JSON encoding reply",
+			args: args{
+				enc:  EncodingJSON,
+				resp: Request{ID: "a1"}.Reply(NewResponseError(errors.New("a"))),
+			},
+			want: `{"id":"a1","type":"ERROR","error":"a"}`,
+		},
+		{
+			name: "JSON encoding ResponseWalk",
+			args: args{
+				enc:  EncodingJSON,
+				resp: NewResponseWalk("night-king", "walker", 1, 2),
+			},
+			want: `{"type":"WALK","enemy":"night-king","x":1,"y":2,"enemyType":"walker"}`,
+		},
+		{
+			name: "JSON encoding ResponseBoom",
+			args: args{
+				enc:  EncodingJSON,
+				resp: NewResponseBoom("mock", "night-king", "walker", 1),
+			},
+			want: `{"type":"BOOM","player":"mock","hits":1,"enemy":"night-king","enemyType":"walker"}`,
+		},
+		{
+			name: "JSON encoding ResponseError",
+			args: args{
+				enc:  EncodingJSON,
+				resp: NewResponseError(errors.New("a")),
+			},
+			want: `{"type":"ERROR","error":"a"}`,
+		},
+		{
+			name: "JSON encoding ResponseHello",
+			args: args{
+				enc:  EncodingJSON,
+				resp: NewResponseHello(2, nil),
+			},
+			want: `{"type":"HELLO","version":2,"capabilities":[]}`,
+		},
+		{
+			name: "JSON encoding ResponseFinish",
+			args: args{
+				enc:  EncodingJSON,
+				resp: NewResponseFinish(true),
+			},
+			want: `{"type":"FINISH","result":"WON"}`,
+		},
+		{
+			name: "JSON encoding ResponseScore",
+			args: args{
+				enc:  EncodingJSON,
+				resp: NewResponseScore([]Score{{Player: "mock", Hits: 2, Misses: 1, Kills: 1, Reaction: 1500 * time.Millisecond}}),
+			},
+			want: `{"type":"SCORE","players":[{"name":"mock","hits":2,"misses":1,"kills":1,"reactionMs":1500}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version := tt.args.version
+			if version == 0 {
+				version = ProtocolVersion
+			}
+			got, err := EncodeResponse(tt.args.enc, version, tt.args.resp)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("EncodeResponse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EncodeResponse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
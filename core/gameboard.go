@@ -1,66 +1,256 @@
 package core
 
 import (
+	"errors"
 	"math/rand"
 	"time"
 )
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
 type Gameboard struct {
-	Zombie *Zombie
+	// Zombies are the zombies still alive on the board,
+	// a zombie is removed from the board once it's dead.
+	Zombies []*Zombie
+	// Width is how far the wall is from the zombies
+	// start, Height is how far they can walk sideways.
+	Width  int
+	Height int
+	// Seed is the seed of the boards random source, a board with
+	// the same seed walks the same way given the same shots.
+	Seed int64
+	rnd  *rand.Rand
+	// types are the zombie types spawned on the board.
+	types []ZombieType
+	// movement is how the spawned zombies walk,
+	// their types decide if it's empty.
+	movement MovementKind
+	// shots are the cells shot at recently, oldest first.
+	shots []Cell
 }
 
 var (
-	maxX = 10
-	maxY = 30
-
-	axiX = "x"
-	axiY = "y"
+	defaultWidth   = 10
+	defaultHeight  = 30
+	defaultZombies = 1
+	// maxBoardSize limits both board dimensions.
+	maxBoardSize = 1000
+	maxZombies   = 20
+	// recentShots is how many of the latest
+	// shots the zombies can try to dodge.
+	recentShots = 10
 )
 
-var axies = []string{axiX, axiY}
+// ErrShotOutOfBoard is returned when a shot
+// is aimed outside of the board.
+var ErrShotOutOfBoard = errors.New("shot is outside of the board")
+
+// ErrTooManyZombies is returned when
+// the board can't fit any more zombies.
+var ErrTooManyZombies = errors.New("board has too many zombies")
+
+// NewGameBoard returns a board as described by the game options, spawning
+// zombies of the given types, or of the first type of the default catalogue
+// if there are none. A random seed is picked if the options don't have one.
+func NewGameBoard(opts GameOptions, types []ZombieType) *Gameboard {
+	g := &Gameboard{
+		Width:    opts.Width,
+		Height:   opts.Height,
+		Seed:     opts.Seed,
+		types:    types,
+		movement: opts.Movement,
+	}
+	g.Spawn(opts.Zombies, opts.HitPoints)
+	return g
+}
+
+// Spawn adds n zombies with unique names to the board, every zombie is
+// of a random type of the board and takes hitPoints plus the hit points
+// of its type to die. New zombies start spread out evenly along the y axis.
+func (g *Gameboard) Spawn(n, hitPoints int) {
+	taken := make(map[string]bool, len(g.Zombies))
+	for _, z := range g.Zombies {
+		taken[z.Name] = true
+	}
+
+	rnd := g.random()
+	types := make([]ZombieType, n)
+	count := make(map[string]int)
+	for i := range types {
+		types[i] = g.zombieType(rnd)
+		count[types[i].Name]++
+	}
+
+	// Names are picked for every type at once, so
+	// zombies of the same type don't share a name.
+	picked := make(map[string][]string, len(count))
+	for _, t := range types {
+		if _, ok := picked[t.Name]; ok {
+			continue
+		}
+		picked[t.Name] = zombieNames(rnd, t.Names, count[t.Name], taken)
+		for _, name := range picked[t.Name] {
+			taken[name] = true
+		}
+	}
+
+	for i, t := range types {
+		hp := hitPoints + t.HitPoints
+		if hp < 1 {
+			hp = 1
+		}
+		z := NewZombie(picked[t.Name][0], hp)
+		picked[t.Name] = picked[t.Name][1:]
+		z.Type = t.Name
+		z.steps = t.Steps
+		z.movement = NewMovement(t.Movement)
+		if g.movement != "" {
+			z.movement = NewMovement(g.movement)
+		}
+		z.y = i * g.Height / n
+		g.Zombies = append(g.Zombies, z)
+	}
+}
 
-func NewGameBoard() *Gameboard {
-	return &Gameboard{
-		Zombie: NewZombie(),
+// zombieType returns a random type of the board, a board
+// without types spawns the first type of the default catalogue.
+func (g *Gameboard) zombieType(rnd *rand.Rand) ZombieType {
+	if len(g.types) == 0 {
+		g.types = DefaultCatalogue()[:1]
+	}
+	// Picking from a single type doesn't take from the
+	// random source, so it doesn't change how games walk.
+	if len(g.types) == 1 {
+		return g.types[0]
 	}
+	return g.types[rnd.Intn(len(g.types))]
 }
 
-// ZombieWalk makes the Zombie walk in random direction
-// returning current x and y coordinates.
-func (g *Gameboard) ZombieWalk() (int, int) {
-	axi := rand.Intn(len(axies))
-	if axies[axi] == axiX && g.Zombie.x < maxX {
-		g.Zombie.x++
+// SpawnZombie adds a single zombie to the board, unless it's full.
+func (g *Gameboard) SpawnZombie(hitPoints int) (*Zombie, error) {
+	if len(g.Zombies) >= maxZombies {
+		return nil, ErrTooManyZombies
 	}
+	g.Spawn(1, hitPoints)
+	return g.Zombies[len(g.Zombies)-1], nil
+}
 
-	if axies[axi] == axiY && g.Zombie.y < maxY {
-		g.Zombie.y++
+// ZombiesWalk makes every Zombie on the board walk as many
+// steps as its type takes, a zombie stops at the wall.
+func (g *Gameboard) ZombiesWalk() {
+	for _, z := range g.Zombies {
+		steps := z.steps
+		if steps < 1 {
+			steps = 1
+		}
+		for i := 0; i < steps; i++ {
+			if x, _ := g.ZombieWalk(z); x >= g.Width {
+				break
+			}
+		}
 	}
-	return g.Zombie.x, g.Zombie.y
 }
 
-// ZombieReachedWall returns true if a Zombie has reached the wall
+// ZombieWalk makes the Zombie walk as its movement decides,
+// returning current x and y coordinates. Zombie never walks
+// off the board.
+func (g *Gameboard) ZombieWalk(z *Zombie) (int, int) {
+	m := z.movement
+	if m == nil {
+		m = RandomWalk{}
+	}
+	x, y := m.Step(Surroundings{
+		X:      z.x,
+		Y:      z.y,
+		Width:  g.Width,
+		Height: g.Height,
+		Shots:  g.shots,
+		Rand:   g.random(),
+	})
+	z.x = clamp(x, 0, g.Width)
+	z.y = clamp(y, 0, g.Height)
+	return z.x, z.y
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// random returns the random source of the board. It's created
+// on first use, so a board can be put together without NewGameBoard.
+func (g *Gameboard) random() *rand.Rand {
+	if g.rnd == nil {
+		if g.Seed == 0 {
+			g.Seed = NewSeed()
+		}
+		g.rnd = rand.New(rand.NewSource(g.Seed))
+	}
+	return g.rnd
+}
+
+// NewSeed returns a seed for a board which wasn't given one.
+func NewSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// ZombieReachedWall returns true if any Zombie has reached the wall
 func (g *Gameboard) ZombieReachedWall() bool {
-	return g.Zombie.x == maxX
+	for _, z := range g.Zombies {
+		if z.x >= g.Width {
+			return true
+		}
+	}
+	return false
 }
 
-// HitZombie tries to hit the Zombie, it returns boolean
-// which describes if the hit was a success.
-func (g *Gameboard) HitZombie(x, y int) bool {
-	hit := false
-	if x == g.Zombie.x && y == g.Zombie.y {
-		g.Zombie.Hits++
-		hit = true
+// ValidateShot returns an error if
+// the shot is aimed outside of the board.
+func (g *Gameboard) ValidateShot(x, y int) error {
+	if x < 0 || x > g.Width || y < 0 || y > g.Height {
+		return ErrShotOutOfBoard
 	}
+	return nil
+}
+
+// HitZombies hits every Zombie standing on the given cell,
+// it returns the zombies which were hit.
+// Zombies which die are removed from the board.
+func (g *Gameboard) HitZombies(x, y int) []*Zombie {
+	g.shots = append(g.shots, Cell{X: x, Y: y})
+	if len(g.shots) > recentShots {
+		g.shots = g.shots[1:]
+	}
+
+	var hit []*Zombie
+	alive := g.Zombies[:0]
+	for _, z := range g.Zombies {
+		if x == z.x && y == z.y {
+			z.Hits++
+			hit = append(hit, z)
+		}
+		if !z.Dead() {
+			alive = append(alive, z)
+		}
+	}
+	g.Zombies = alive
 
 	return hit
 }
 
-// ZombieDead returns whether the Zombie is dead.
-func (g *Gameboard) ZombieDead() bool {
-	return g.Zombie.Hits >= 3
+// ZombiesDead returns whether every Zombie is dead.
+func (g *Gameboard) ZombiesDead() bool {
+	return len(g.Zombies) == 0
+}
+
+// ZombiesInfo returns the state of the zombies on the board.
+func (g *Gameboard) ZombiesInfo() []ZombieInfo {
+	info := make([]ZombieInfo, 0, len(g.Zombies))
+	for _, z := range g.Zombies {
+		info = append(info, z.Info())
+	}
+	return info
 }
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -23,15 +24,18 @@ func TestGameboard_ZombieWalk(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			z := &Zombie{
+				x: tt.fields.x,
+				y: tt.fields.y,
+			}
 			g := &Gameboard{
-				Zombie: &Zombie{
-					x: 0,
-					y: 0,
-				},
+				Zombies: []*Zombie{z},
+				Width:   defaultWidth,
+				Height:  defaultHeight,
 			}
 			// Sort of annoying to test considering that we have
 			// a random seed.
-			got, got1 := g.ZombieWalk()
+			got, got1 := g.ZombieWalk(z)
 			if got == tt.fields.x && got1 == tt.fields.y {
 				t.Errorf("Gameboard.ZombieWalk() didn't update neither x or y")
 			}
@@ -52,7 +56,7 @@ func TestGameboard_ZombieReachedWall(t *testing.T) {
 		{
 			name: "zombie has reached the wall, should return true",
 			fields: fields{
-				x: maxX,
+				x: defaultWidth,
 				y: 0,
 			},
 			want: true,
@@ -69,7 +73,7 @@ func TestGameboard_ZombieReachedWall(t *testing.T) {
 			name: "zombie has not reached the wall, should return false",
 			fields: fields{
 				x: 0,
-				y: maxY,
+				y: defaultHeight,
 			},
 			want: false,
 		},
@@ -77,10 +81,12 @@ func TestGameboard_ZombieReachedWall(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := &Gameboard{
-				Zombie: &Zombie{
-					x: tt.fields.x,
-					y: tt.fields.y,
+				Zombies: []*Zombie{
+					{x: 0, y: 0},
+					{x: tt.fields.x, y: tt.fields.y},
 				},
+				Width:  defaultWidth,
+				Height: defaultHeight,
 			}
 			if got := g.ZombieReachedWall(); got != tt.want {
 				t.Errorf("Gameboard.ZombieReachedWall() = %v, want %v", got, tt.want)
@@ -89,68 +95,195 @@ func TestGameboard_ZombieReachedWall(t *testing.T) {
 	}
 }
 
-func TestGameboard_ZombieHit(t *testing.T) {
-	type fields struct {
+func TestGameboard_ZombieWalkStaysOnBoard(t *testing.T) {
+	kinds := []MovementKind{MovementRandom, MovementCharge, MovementZigZag, MovementDodge, MovementWander}
+	for _, kind := range kinds {
+		t.Run(string(kind), func(t *testing.T) {
+			z := &Zombie{HitPoints: 1, movement: NewMovement(kind)}
+			g := &Gameboard{
+				Zombies: []*Zombie{z},
+				Width:   2,
+				Height:  1,
+			}
+			for i := 0; i < 100; i++ {
+				// Shots next to the zombie make it dodge, they never hit it.
+				g.HitZombies(z.x+1, z.y)
+				x, y := g.ZombieWalk(z)
+				if x < 0 || y < 0 || x > g.Width || y > g.Height {
+					t.Fatalf("Gameboard.ZombieWalk() = %v %v, walked outside of %vx%v board", x, y, g.Width, g.Height)
+				}
+				if zx, zy := z.Position(); zx != x || zy != y {
+					t.Fatalf("Zombie.Position() = %v %v, want %v %v", zx, zy, x, y)
+				}
+			}
+			if len(g.Zombies) != 1 {
+				t.Errorf("Gameboard has %v zombies, want the zombie to stay alive", len(g.Zombies))
+			}
+		})
+	}
+}
+
+func TestGameboard_HitZombiesRemembersShots(t *testing.T) {
+	g := &Gameboard{Width: defaultWidth, Height: defaultHeight}
+	for i := 0; i <= recentShots; i++ {
+		g.HitZombies(i, 0)
+	}
+	if len(g.shots) != recentShots {
+		t.Fatalf("Gameboard remembers %v shots, want %v", len(g.shots), recentShots)
+	}
+	if g.shots[0] != (Cell{1, 0}) {
+		t.Errorf("Gameboard oldest shot = %v, want %v", g.shots[0], Cell{1, 0})
+	}
+}
+
+func TestGameboard_ValidateShot(t *testing.T) {
+	type args struct {
 		x int
 		y int
 	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "shot inside the board, should not error",
+			args: args{
+				x: 20,
+				y: 50,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "shot with negative coordinate, should error",
+			args: args{
+				x: -1,
+				y: 0,
+			},
+			wantErr: ErrShotOutOfBoard,
+		},
+		{
+			name: "shot outside of board width, should error",
+			args: args{
+				x: 21,
+				y: 0,
+			},
+			wantErr: ErrShotOutOfBoard,
+		},
+		{
+			name: "shot outside of board height, should error",
+			args: args{
+				x: 0,
+				y: 51,
+			},
+			wantErr: ErrShotOutOfBoard,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGameBoard(GameOptions{Width: 20, Height: 50, Zombies: defaultZombies, HitPoints: defaultHitPoints}, nil)
+			if err := g.ValidateShot(tt.args.x, tt.args.y); err != tt.wantErr {
+				t.Errorf("Gameboard.ValidateShot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGameboard_HitZombies(t *testing.T) {
+	type fields struct {
+		zombies []*Zombie
+	}
 	type args struct {
 		x int
 		y int
 	}
 	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   bool
+		name      string
+		fields    fields
+		args      args
+		wantHit   int
+		wantAlive int
 	}{
 		{
-			name: "hit was a success should return true",
+			name: "hit was a success should return the zombie",
 			fields: fields{
+				zombies: []*Zombie{{x: 0, y: 1, HitPoints: defaultHitPoints}},
+			},
+			args: args{
 				x: 0,
 				y: 1,
 			},
+			wantHit:   1,
+			wantAlive: 1,
+		},
+		{
+			name: "hit was a falure should return nothing",
+			fields: fields{
+				zombies: []*Zombie{{x: 1, y: 1, HitPoints: defaultHitPoints}},
+			},
 			args: args{
 				x: 0,
 				y: 1,
 			},
-			want: true,
+			wantHit:   0,
+			wantAlive: 1,
 		},
 		{
-			name: "hit was a falure should return false",
+			name: "every zombie on the cell should be hit",
 			fields: fields{
-				x: 1,
+				zombies: []*Zombie{
+					{x: 0, y: 1, HitPoints: defaultHitPoints},
+					{x: 0, y: 1, HitPoints: defaultHitPoints},
+					{x: 1, y: 1, HitPoints: defaultHitPoints},
+				},
+			},
+			args: args{
+				x: 0,
 				y: 1,
 			},
+			wantHit:   2,
+			wantAlive: 3,
+		},
+		{
+			name: "killed zombie should be removed from the board",
+			fields: fields{
+				zombies: []*Zombie{
+					{x: 0, y: 1, Hits: defaultHitPoints - 1, HitPoints: defaultHitPoints},
+					{x: 1, y: 1, HitPoints: defaultHitPoints},
+				},
+			},
 			args: args{
 				x: 0,
 				y: 1,
 			},
-			want: false,
+			wantHit:   1,
+			wantAlive: 1,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := &Gameboard{
-				Zombie: &Zombie{
-					x: tt.fields.x,
-					y: tt.fields.y,
-				},
+				Zombies: tt.fields.zombies,
+			}
+			hit := g.HitZombies(tt.args.x, tt.args.y)
+			if len(hit) != tt.wantHit {
+				t.Errorf("Gameboard.HitZombies() hit %v zombies, want %v", len(hit), tt.wantHit)
 			}
-			hit := g.HitZombie(tt.args.x, tt.args.y)
-			if hit != tt.want {
-				t.Errorf("Gameboard.HitZombie() = %v, want %v", hit, tt.want)
+			for _, z := range hit {
+				if z.Hits == 0 {
+					t.Errorf("Gameboard.HitZombies() should increment hits")
+				}
 			}
-			if hit && g.Zombie.Hits == 0 {
-				t.Errorf("Gameboard.HitZombie() should increments hits")
+			if len(g.Zombies) != tt.wantAlive {
+				t.Errorf("Gameboard.HitZombies() left %v zombies, want %v", len(g.Zombies), tt.wantAlive)
 			}
 		})
 	}
 }
 
-func TestGameboard_ZombieDead(t *testing.T) {
+func TestGameboard_ZombiesDead(t *testing.T) {
 	type fields struct {
-		Hits int
+		zombies []*Zombie
 	}
 	tests := []struct {
 		name   string
@@ -158,29 +291,204 @@ func TestGameboard_ZombieDead(t *testing.T) {
 		want   bool
 	}{
 		{
-			name: "zombie isn't dead yet, should return false",
+			name: "zombies aren't dead yet, should return false",
 			fields: fields{
-				Hits: 1,
+				zombies: []*Zombie{{Hits: 1, HitPoints: defaultHitPoints}},
 			},
 			want: false,
 		},
 		{
-			name: "zombie is dead, should return true",
+			name: "zombies are dead, should return true",
 			fields: fields{
-				Hits: 1,
+				zombies: nil,
 			},
-			want: false,
+			want: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := &Gameboard{
-				Zombie: &Zombie{
-					Hits: tt.fields.Hits,
-				},
+				Zombies: tt.fields.zombies,
+			}
+			if got := g.ZombiesDead(); got != tt.want {
+				t.Errorf("Gameboard.ZombiesDead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGameBoard(t *testing.T) {
+	g := NewGameBoard(GameOptions{Width: defaultWidth, Height: defaultHeight, Zombies: len(names) + 2, HitPoints: defaultHitPoints}, nil)
+
+	seen := make(map[string]bool)
+	for _, z := range g.Zombies {
+		if seen[z.Name] {
+			t.Errorf("NewGameBoard() zombie name %v is not unique", z.Name)
+		}
+		seen[z.Name] = true
+	}
+	if len(seen) != len(names)+2 {
+		t.Errorf("NewGameBoard() created %v zombies, want %v", len(seen), len(names)+2)
+	}
+}
+
+func TestNewGameBoard_Seed(t *testing.T) {
+	// play walks and shoots at the first zombie every
+	// turn, returning the positions of the zombies.
+	play := func(g *Gameboard) []ZombieInfo {
+		var walked []ZombieInfo
+		for i := 0; i < 20; i++ {
+			g.ZombiesWalk()
+			walked = append(walked, g.ZombiesInfo()...)
+			if !g.ZombiesDead() {
+				x, y := g.Zombies[0].Position()
+				g.HitZombies(x, y)
+			}
+			if i == 10 {
+				g.Spawn(3, defaultHitPoints)
+			}
+		}
+		return walked
+	}
+
+	a := NewGameBoard(GameOptions{Width: defaultWidth, Height: defaultHeight, Zombies: 5, HitPoints: defaultHitPoints, Seed: 42}, nil)
+	b := NewGameBoard(GameOptions{Width: defaultWidth, Height: defaultHeight, Zombies: 5, HitPoints: defaultHitPoints, Seed: 42}, nil)
+	if !reflect.DeepEqual(play(a), play(b)) {
+		t.Errorf("boards with the same seed played differently")
+	}
+
+	c := NewGameBoard(GameOptions{Width: defaultWidth, Height: defaultHeight, Zombies: 5, HitPoints: defaultHitPoints}, nil)
+	if c.Seed == 0 {
+		t.Errorf("NewGameBoard() didn't pick a seed")
+	}
+}
+
+func TestGameboard_Spawn(t *testing.T) {
+	g := NewGameBoard(GameOptions{Width: defaultWidth, Height: defaultHeight, Zombies: 2, HitPoints: defaultHitPoints}, nil)
+	g.Spawn(len(names), defaultHitPoints+1)
+
+	seen := make(map[string]bool)
+	for _, z := range g.Zombies {
+		if seen[z.Name] {
+			t.Errorf("Gameboard.Spawn() zombie name %v is not unique", z.Name)
+		}
+		seen[z.Name] = true
+	}
+	if len(g.Zombies) != len(names)+2 {
+		t.Errorf("Gameboard.Spawn() board has %v zombies, want %v", len(g.Zombies), len(names)+2)
+	}
+	if hp := g.Zombies[len(g.Zombies)-1].HitPoints; hp != defaultHitPoints+1 {
+		t.Errorf("Gameboard.Spawn() zombie has %v hit points, want %v", hp, defaultHitPoints+1)
+	}
+}
+
+func TestGameboard_SpawnTypes(t *testing.T) {
+	types, err := DefaultCatalogue().Lookup([]string{"runner", "brute"})
+	if err != nil {
+		t.Fatalf("Catalogue.Lookup() error = %v", err)
+	}
+	g := NewGameBoard(GameOptions{Width: defaultWidth, Height: defaultHeight, Zombies: maxZombies, HitPoints: defaultHitPoints, Seed: 42}, types)
+
+	seen := make(map[string]bool)
+	for _, z := range g.Zombies {
+		if seen[z.Name] {
+			t.Errorf("NewGameBoard() zombie name %v is not unique", z.Name)
+		}
+		seen[z.Name] = true
+
+		var want ZombieType
+		for _, tt := range types {
+			if tt.Name == z.Type {
+				want = tt
+			}
+		}
+		if want.Name == "" {
+			t.Fatalf("NewGameBoard() spawned a zombie of type %q", z.Type)
+		}
+		if z.HitPoints != defaultHitPoints+want.HitPoints {
+			t.Errorf("NewGameBoard() %v has %v hit points, want %v", z.Type, z.HitPoints, defaultHitPoints+want.HitPoints)
+		}
+		if z.steps != want.Steps {
+			t.Errorf("NewGameBoard() %v takes %v steps, want %v", z.Type, z.steps, want.Steps)
+		}
+	}
+}
+
+func TestGameboard_ZombiesWalkSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps int
+		x     int
+		wantX int
+	}{
+		{
+			name:  "single step, should walk once",
+			steps: 1,
+			x:     0,
+			wantX: 1,
+		},
+		{
+			name:  "many steps, should walk every step",
+			steps: 3,
+			x:     0,
+			wantX: 3,
+		},
+		{
+			name:  "wall in the way, should stop at the wall",
+			steps: 3,
+			x:     defaultWidth - 1,
+			wantX: defaultWidth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z := &Zombie{x: tt.x, steps: tt.steps, movement: Charge{}}
+			g := &Gameboard{
+				Zombies: []*Zombie{z},
+				Width:   defaultWidth,
+				Height:  defaultHeight,
+			}
+			g.ZombiesWalk()
+			if x, _ := z.Position(); x != tt.wantX {
+				t.Errorf("Gameboard.ZombiesWalk() x = %v, want %v", x, tt.wantX)
+			}
+		})
+	}
+}
+
+func TestGameboard_SpawnZombie(t *testing.T) {
+	tests := []struct {
+		name    string
+		zombies int
+		wantErr bool
+	}{
+		{
+			name:    "board has room, should spawn",
+			zombies: 1,
+			wantErr: false,
+		},
+		{
+			name:    "board is full, should error",
+			zombies: maxZombies,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGameBoard(GameOptions{Width: defaultWidth, Height: defaultHeight, Zombies: tt.zombies, HitPoints: defaultHitPoints}, nil)
+			z, err := g.SpawnZombie(defaultHitPoints + 1)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Gameboard.SpawnZombie() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(g.Zombies) != tt.zombies+1 || g.Zombies[tt.zombies] != z {
+				t.Errorf("Gameboard.SpawnZombie() zombie wasn't added to the board")
 			}
-			if got := g.ZombieDead(); got != tt.want {
-				t.Errorf("Gameboard.ZombieDead() = %v, want %v", got, tt.want)
+			if z.HitPoints != defaultHitPoints+1 {
+				t.Errorf("Gameboard.SpawnZombie() zombie has %v hit points, want %v", z.HitPoints, defaultHitPoints+1)
 			}
 		})
 	}
@@ -0,0 +1,73 @@
+package core
+
+import "sort"
+
+// DefaultLeaderboardSize is how many players are listed
+// when the client doesn't ask for a specific number.
+const DefaultLeaderboardSize = 10
+
+// maxLeaderboardSize limits how many players can be listed at once.
+const maxLeaderboardSize = 100
+
+// Result describes how a single player did in a finished game.
+type Result struct {
+	Player string `json:"player"`
+	Won    bool   `json:"won"`
+	Kills  int    `json:"kills"`
+	Hits   int    `json:"hits"`
+	Shots  int    `json:"shots"`
+}
+
+// Standing describes how a player did over every game he has finished.
+type Standing struct {
+	Player string
+	Wins   int
+	Losses int
+	Kills  int
+	Hits   int
+	Shots  int
+}
+
+// Add counts a result of a finished game in to the standing.
+func (s *Standing) Add(r Result) {
+	if r.Won {
+		s.Wins++
+	} else {
+		s.Losses++
+	}
+	s.Kills += r.Kills
+	s.Hits += r.Hits
+	s.Shots += r.Shots
+}
+
+// Accuracy returns the share of shots which hit a zombie.
+func (s *Standing) Accuracy() float64 {
+	if s.Shots == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Shots)
+}
+
+// RankStandings sorts the standings best players first.
+// Players are ranked by wins, then kills and then by name.
+func RankStandings(standings []Standing) {
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.Kills != b.Kills {
+			return a.Kills > b.Kills
+		}
+		return a.Player < b.Player
+	})
+}
+
+// Leaderboard stores how players did across games.
+// Implementations must be safe for concurrent use.
+type Leaderboard interface {
+	// Record counts the results of a finished game.
+	Record(results []Result) error
+	// Top returns at most n best players.
+	Top(n int) ([]Standing, error)
+}
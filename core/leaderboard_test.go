@@ -0,0 +1,107 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStanding_Add(t *testing.T) {
+	type args struct {
+		results []Result
+	}
+	tests := []struct {
+		name string
+		args args
+		want Standing
+	}{
+		{
+			name: "no results, should be empty",
+			args: args{},
+			want: Standing{Player: "A"},
+		},
+		{
+			name: "won and lost games, should sum up",
+			args: args{
+				results: []Result{
+					{Player: "A", Won: true, Kills: 1, Hits: 3, Shots: 4},
+					{Player: "A", Won: false, Kills: 0, Hits: 1, Shots: 2},
+				},
+			},
+			want: Standing{Player: "A", Wins: 1, Losses: 1, Kills: 1, Hits: 4, Shots: 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Standing{Player: "A"}
+			for _, r := range tt.args.results {
+				got.Add(r)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Standing.Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStanding_Accuracy(t *testing.T) {
+	tests := []struct {
+		name     string
+		standing Standing
+		want     float64
+	}{
+		{
+			name:     "no shots, should be 0",
+			standing: Standing{},
+			want:     0,
+		},
+		{
+			name:     "some shots hit, should be the share of hits",
+			standing: Standing{Hits: 1, Shots: 4},
+			want:     0.25,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.standing.Accuracy(); got != tt.want {
+				t.Errorf("Standing.Accuracy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankStandings(t *testing.T) {
+	type args struct {
+		standings []Standing
+	}
+	tests := []struct {
+		name string
+		args args
+		want []Standing
+	}{
+		{
+			name: "should rank by wins, kills and name",
+			args: args{
+				standings: []Standing{
+					{Player: "D"},
+					{Player: "C", Wins: 1},
+					{Player: "B", Wins: 1, Kills: 2},
+					{Player: "A"},
+				},
+			},
+			want: []Standing{
+				{Player: "B", Wins: 1, Kills: 2},
+				{Player: "C", Wins: 1},
+				{Player: "A"},
+				{Player: "D"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RankStandings(tt.args.standings)
+			if !reflect.DeepEqual(tt.args.standings, tt.want) {
+				t.Errorf("RankStandings() = %v, want %v", tt.args.standings, tt.want)
+			}
+		})
+	}
+}
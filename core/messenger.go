@@ -1,6 +1,9 @@
 package core
 
 import (
+	"sync"
+	"time"
+
 	"bitbucket.org/advbet/uid"
 )
 
@@ -10,6 +13,13 @@ type Messenger struct {
 	send      chan Message
 	resp      chan Response
 	signature uid.UUID
+
+	// Protocol settings are read by the connection writer
+	// while the reader might be changing them.
+	enc     Encoding
+	version int
+	caps    []Capability
+	m       sync.RWMutex
 }
 
 // Message is a message sent over the Messenger.
@@ -18,6 +28,32 @@ type Message struct {
 	Message   string
 	Resp      chan Response
 	Signature uid.UUID
+	// Request describes how the message was received,
+	// responses to the message are tagged with it.
+	Request Request
+}
+
+// Request describes how a command was received from the client.
+type Request struct {
+	// ID is the request ID the client has tagged the command with.
+	ID string
+	// Received is when the command was read from the connection.
+	Received time.Time
+}
+
+// Reply is a response to a single command, it carries the request
+// so the response can be tagged with the request ID.
+type Reply struct {
+	Response
+	Request Request
+}
+
+// Reply returns the response as a reply to the request.
+func (r Request) Reply(resp Response) *Reply {
+	return &Reply{
+		Response: resp,
+		Request:  r,
+	}
 }
 
 // NewMessenger returns a new messenger object.
@@ -27,23 +63,75 @@ func NewMessenger(signature uid.UUID, send chan Message) *Messenger {
 		signature: signature,
 		send:      send,
 		resp:      make(chan Response),
+		enc:       EncodingText,
+		version:   ProtocolVersionLegacy,
+	}
+}
+
+// SetProtocol sets the negotiated protocol version
+// and capabilities of the messenger owner.
+func (m *Messenger) SetProtocol(version int, caps []Capability) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.version = version
+	m.caps = caps
+}
+
+// Version returns the protocol version of the messenger owner.
+func (m *Messenger) Version() int {
+	m.m.RLock()
+	defer m.m.RUnlock()
+	return m.version
+}
+
+// HasCapability returns whether the given capability
+// was negotiated with the messenger owner.
+func (m *Messenger) HasCapability(c Capability) bool {
+	m.m.RLock()
+	defer m.m.RUnlock()
+	for _, have := range m.caps {
+		if have == c {
+			return true
+		}
 	}
+	return false
+}
+
+// SetEncoding sets the encoding the messenger owner
+// is using to communicate.
+func (m *Messenger) SetEncoding(enc Encoding) {
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.enc = enc
+}
+
+// Encoding returns the encoding the messenger owner
+// is using to communicate.
+func (m *Messenger) Encoding() Encoding {
+	m.m.RLock()
+	defer m.m.RUnlock()
+	return m.enc
 }
 
 // SendMessage sends a new message
 func (m *Messenger) SendMessage(s string) {
+	m.SendRequest(s, Request{})
+}
+
+// SendRequest sends a new message received as the given request.
+func (m *Messenger) SendRequest(s string, req Request) {
 	m.send <- Message{
 		Message:   s,
 		Resp:      m.resp,
 		Signature: m.signature,
+		Request:   req,
 	}
 }
 
-// Disconnect sends a new disconnet message
-// closing the response channel.
+// Disconnect sends a new disconnet message, the receiver
+// closes the response channel once it has handled it.
 // After disconnet is called, messenger is no longer valid.
 func (m *Messenger) Disconnect() {
-	close(m.resp)
 	m.send <- Message{
 		DC:        true,
 		Resp:      m.resp,
@@ -51,9 +139,10 @@ func (m *Messenger) Disconnect() {
 	}
 }
 
-// Respond sents a response back to the message creator.
+// Respond sents a response back to the message creator,
+// it's sent as a reply to the request of the message.
 func (m *Message) Respond(resp Response) {
-	m.Resp <- resp
+	m.Resp <- m.Request.Reply(resp)
 }
 
 // RespondErr sends a response (error) back to the message creator.
@@ -61,6 +150,18 @@ func (m *Message) RespondErr(err error) {
 	m.Respond(NewResponseError(err))
 }
 
+// Close closes the response channel of a disconnect message,
+// nothing can be sent to the message creator afterwards.
+func (m *Message) Close() {
+	close(m.Resp)
+}
+
+// Respond sends a response straight back to the messenger
+// owner, it is used when a message can't be passed along.
+func (m *Messenger) Respond(resp Response) {
+	m.resp <- resp
+}
+
 func (m *Messenger) ReadResponses() <-chan Response {
 	return m.resp
 }
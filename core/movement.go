@@ -0,0 +1,164 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Movement decides where a zombie walks. A zombie has its own
+// movement, so a movement can keep track of how it has walked.
+type Movement interface {
+	// Step returns where the zombie walks next, it's
+	// kept on the board even if it's aimed outside.
+	Step(s Surroundings) (x, y int)
+}
+
+// Surroundings describe the board around a zombie which is about to walk.
+type Surroundings struct {
+	// X and Y are the current coordinates of the zombie.
+	X, Y int
+	// Width is how far the wall is, Height is
+	// how far the zombie can walk sideways.
+	Width, Height int
+	// Shots are the cells the players have shot at recently, oldest first.
+	Shots []Cell
+	// Rand is the random source of the board, movements must
+	// not use any other, so games can be played out again.
+	Rand *rand.Rand
+}
+
+// Cell is a single cell of the board.
+type Cell struct {
+	X, Y int
+}
+
+// MovementKind is a type which describes
+// how the zombies of a game walk.
+type MovementKind string
+
+const (
+	// MovementRandom walks either forward or sideways at random.
+	MovementRandom MovementKind = "random"
+	// MovementCharge walks straight for the wall.
+	MovementCharge MovementKind = "charge"
+	// MovementZigZag walks forward and sideways in turns,
+	// changing the sideways direction every time.
+	MovementZigZag MovementKind = "zigzag"
+	// MovementDodge walks forward unless the players have recently
+	// shot at the cell, then it tries to step aside instead.
+	MovementDodge MovementKind = "dodge"
+	// MovementWander mostly walks forward, but sometimes
+	// steps sideways or even backwards.
+	MovementWander MovementKind = "wander"
+)
+
+// ParseMovementKind returns the movement kind with the given name.
+func ParseMovementKind(s string) (MovementKind, error) {
+	switch k := MovementKind(s); k {
+	case MovementRandom, MovementCharge, MovementZigZag, MovementDodge, MovementWander:
+		return k, nil
+	}
+	return "", fmt.Errorf("movement should be one of: %s, %s, %s, %s, %s",
+		MovementRandom, MovementCharge, MovementZigZag, MovementDodge, MovementWander)
+}
+
+// NewMovement returns a new movement of the given kind,
+// unknown kinds walk at random.
+func NewMovement(kind MovementKind) Movement {
+	switch kind {
+	case MovementCharge:
+		return Charge{}
+	case MovementZigZag:
+		return &ZigZag{}
+	case MovementDodge:
+		return Dodge{}
+	case MovementWander:
+		return Wander{}
+	}
+	return RandomWalk{}
+}
+
+// RandomWalk walks either forward or sideways at random.
+type RandomWalk struct{}
+
+func (RandomWalk) Step(s Surroundings) (int, int) {
+	if s.Rand.Intn(2) == 0 {
+		return s.X + 1, s.Y
+	}
+	return s.X, s.Y + 1
+}
+
+// Charge walks straight for the wall.
+type Charge struct{}
+
+func (Charge) Step(s Surroundings) (int, int) {
+	return s.X + 1, s.Y
+}
+
+// ZigZag walks forward and sideways in turns, changing the
+// sideways direction every time or when it can't go further.
+type ZigZag struct {
+	steps int
+}
+
+func (m *ZigZag) Step(s Surroundings) (int, int) {
+	m.steps++
+	if m.steps%2 == 1 {
+		return s.X + 1, s.Y
+	}
+
+	dir := 1
+	if (m.steps/2)%2 == 0 {
+		dir = -1
+	}
+	if y := s.Y + dir; y < 0 || y > s.Height {
+		dir = -dir
+	}
+	return s.X, s.Y + dir
+}
+
+// Dodge walks forward unless the players have recently shot at the
+// cell, then it steps aside to a cell which wasn't shot at. If every
+// cell around it was shot at, it charges.
+type Dodge struct{}
+
+func (Dodge) Step(s Surroundings) (int, int) {
+	shot := make(map[Cell]bool, len(s.Shots))
+	for _, c := range s.Shots {
+		shot[c] = true
+	}
+
+	forward := Cell{s.X + 1, s.Y}
+	if !shot[forward] {
+		return forward.X, forward.Y
+	}
+
+	sides := []Cell{{s.X, s.Y - 1}, {s.X, s.Y + 1}}
+	if s.Rand.Intn(2) == 0 {
+		sides[0], sides[1] = sides[1], sides[0]
+	}
+	for _, c := range sides {
+		if c.Y >= 0 && c.Y <= s.Height && !shot[c] {
+			return c.X, c.Y
+		}
+	}
+	return forward.X, forward.Y
+}
+
+// Wander walks forward six times out of ten, it steps to
+// either side three times out of ten and backwards otherwise.
+// Both sides are equally likely.
+type Wander struct{}
+
+func (Wander) Step(s Surroundings) (int, int) {
+	switch n := s.Rand.Intn(10); {
+	case n < 6:
+		return s.X + 1, s.Y
+	case n < 9:
+		if s.Rand.Intn(2) == 0 {
+			return s.X, s.Y - 1
+		}
+		return s.X, s.Y + 1
+	}
+	return s.X - 1, s.Y
+}
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestParseMovementKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    MovementKind
+		wantErr bool
+	}{
+		{
+			name: "known movement, should not error",
+			s:    "zigzag",
+			want: MovementZigZag,
+		},
+		{
+			name:    "unknown movement, should error",
+			s:       "mock",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMovementKind(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseMovementKind() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseMovementKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMovement_Step(t *testing.T) {
+	tests := []struct {
+		name     string
+		movement Movement
+		shots    []Cell
+		x, y     int
+		// want are the cells the zombie may walk to.
+		want []Cell
+	}{
+		{
+			name:     "random walk, should walk forward or sideways",
+			movement: RandomWalk{},
+			x:        2,
+			y:        2,
+			want:     []Cell{{3, 2}, {2, 3}},
+		},
+		{
+			name:     "charge, should walk forward",
+			movement: Charge{},
+			x:        2,
+			y:        2,
+			want:     []Cell{{3, 2}},
+		},
+		{
+			name:     "dodge with nothing shot, should walk forward",
+			movement: Dodge{},
+			x:        2,
+			y:        2,
+			want:     []Cell{{3, 2}},
+		},
+		{
+			name:     "dodge with the cell ahead shot, should step aside",
+			movement: Dodge{},
+			shots:    []Cell{{3, 2}},
+			x:        2,
+			y:        2,
+			want:     []Cell{{2, 1}, {2, 3}},
+		},
+		{
+			name:     "dodge with a side shot too, should step to the other side",
+			movement: Dodge{},
+			shots:    []Cell{{3, 2}, {2, 1}},
+			x:        2,
+			y:        2,
+			want:     []Cell{{2, 3}},
+		},
+		{
+			name:     "dodge with every cell around shot, should walk forward",
+			movement: Dodge{},
+			shots:    []Cell{{3, 2}, {2, 1}, {2, 3}},
+			x:        2,
+			y:        2,
+			want:     []Cell{{3, 2}},
+		},
+		{
+			name:     "dodge at the edge, should not step off the board",
+			movement: Dodge{},
+			shots:    []Cell{{3, 0}, {2, 1}},
+			x:        2,
+			y:        0,
+			want:     []Cell{{3, 0}},
+		},
+		{
+			name:     "wander, should take a single step",
+			movement: Wander{},
+			x:        2,
+			y:        2,
+			want:     []Cell{{3, 2}, {2, 1}, {2, 3}, {1, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 50; i++ {
+				x, y := tt.movement.Step(Surroundings{
+					X:      tt.x,
+					Y:      tt.y,
+					Width:  defaultWidth,
+					Height: defaultHeight,
+					Shots:  tt.shots,
+					Rand:   rnd,
+				})
+				if !containsCell(tt.want, Cell{x, y}) {
+					t.Fatalf("Step() = %v, %v, want one of %v", x, y, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestZigZag_Step(t *testing.T) {
+	m := NewMovement(MovementZigZag)
+	s := Surroundings{Width: defaultWidth, Height: defaultHeight, Rand: rand.New(rand.NewSource(1))}
+	want := []Cell{{1, 0}, {1, 1}, {2, 1}, {2, 0}, {3, 0}, {3, 1}}
+	for i, w := range want {
+		s.X, s.Y = m.Step(s)
+		if (Cell{s.X, s.Y}) != w {
+			t.Fatalf("step %d = %v, %v, want %v", i+1, s.X, s.Y, w)
+		}
+	}
+}
+
+func TestWander_StepDistribution(t *testing.T) {
+	const steps = 10000
+	m := NewMovement(MovementWander)
+	s := Surroundings{X: 5, Y: 5, Width: defaultWidth, Height: defaultHeight, Rand: rand.New(rand.NewSource(1))}
+
+	got := make(map[Cell]int)
+	for i := 0; i < steps; i++ {
+		x, y := m.Step(s)
+		got[Cell{x - s.X, y - s.Y}]++
+	}
+
+	want := map[Cell]float64{
+		{1, 0}:  0.6,
+		{0, -1}: 0.15,
+		{0, 1}:  0.15,
+		{-1, 0}: 0.1,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("stepped to %v, want only %v", got, want)
+	}
+	for c, p := range want {
+		if share := float64(got[c]) / steps; share < p-0.02 || share > p+0.02 {
+			t.Errorf("step %v share = %.3f, want %.2f", c, share, p)
+		}
+	}
+}
+
+func containsCell(cells []Cell, c Cell) bool {
+	for _, cc := range cells {
+		if cc == c {
+			return true
+		}
+	}
+	return false
+}
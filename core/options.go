@@ -0,0 +1,184 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MissMode is a type which describes who
+// is notified about a missed shot.
+type MissMode string
+
+const (
+	// MissModeShooter notifies only the player who missed.
+	MissModeShooter MissMode = "shooter"
+	// MissModeAll notifies every player in the game.
+	MissModeAll MissMode = "all"
+)
+
+// GameMode is a type which describes
+// the rules a game is played by.
+type GameMode string
+
+const (
+	// GameModeClassic is won once all the zombies are dead.
+	GameModeClassic GameMode = "classic"
+	// GameModeWaves spawns a new and harder wave of zombies every
+	// time one is cleared, it's played until the zombies win.
+	GameModeWaves GameMode = "waves"
+	// GameModeCompetitive is won by the player who
+	// lands the first killing shot, it needs 2+ players.
+	GameModeCompetitive GameMode = "competitive"
+)
+
+// CompetitivePlayers is how many players a
+// competitive game needs before anyone can shoot.
+const CompetitivePlayers = 2
+
+// GameOptions describe how a game is played,
+// they are picked when the game is created.
+type GameOptions struct {
+	Mode       GameMode
+	Misses     MissMode
+	Difficulty Difficulty
+	Width      int
+	Height     int
+	Zombies    int
+	// HitPoints is how many hits a zombie takes to die.
+	HitPoints int
+	// Tick is how often the zombies walk.
+	Tick time.Duration
+	// Seed makes the game play out the same way every time,
+	// a random seed is picked for the game if it's 0.
+	Seed int64
+	// Movement is how every zombie walks,
+	// their types decide if it's empty.
+	Movement MovementKind
+	// Types are the names of the zombie types spawned in the
+	// game, the first type of the catalogue is used if it's empty.
+	Types []string
+}
+
+var (
+	// minTick and maxTick limit how often the zombies walk.
+	minTick = 250 * time.Millisecond
+	maxTick = time.Minute
+	// maxHitPoints limits how tough the zombies are.
+	maxHitPoints = 100
+)
+
+// DefaultGameOptions returns options used when
+// a game is created without any options given.
+func DefaultGameOptions() GameOptions {
+	return GameOptions{
+		Mode:   GameModeClassic,
+		Misses: MissModeShooter,
+	}.WithDifficulty(DifficultyNormal)
+}
+
+// ParseGameOptions parses options given as `key=value`
+// arguments, options which aren't given are left default.
+func ParseGameOptions(args []string) (GameOptions, error) {
+	return DefaultGameOptions().Apply(args)
+}
+
+// Apply returns the options changed by the `key=value` arguments. The
+// difficulty is applied first, so the rest of the arguments override it.
+func (o GameOptions) Apply(args []string) (GameOptions, error) {
+	keys := make([]string, 0, len(args))
+	vals := make([]string, 0, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return o, fmt.Errorf("expected format for an option is '{key}={value}', got '%s'", arg)
+		}
+		keys = append(keys, parts[0])
+		vals = append(vals, parts[1])
+	}
+
+	for i, key := range keys {
+		if key != "difficulty" {
+			continue
+		}
+		d, err := ParseDifficulty(vals[i])
+		if err != nil {
+			return o, fmt.Errorf("option %w", err)
+		}
+		o = o.WithDifficulty(d)
+	}
+
+	for i, key := range keys {
+		val := vals[i]
+		switch key {
+		case "difficulty":
+			// Already applied.
+		case "mode":
+			switch GameMode(val) {
+			case GameModeClassic, GameModeWaves, GameModeCompetitive:
+				o.Mode = GameMode(val)
+			default:
+				return o, fmt.Errorf("option mode should be one of: %s, %s, %s", GameModeClassic, GameModeWaves, GameModeCompetitive)
+			}
+		case "misses":
+			switch MissMode(val) {
+			case MissModeShooter, MissModeAll:
+				o.Misses = MissMode(val)
+			default:
+				return o, fmt.Errorf("option misses should be one of: %s, %s", MissModeShooter, MissModeAll)
+			}
+		case "width", "height":
+			size, err := strconv.Atoi(val)
+			if err != nil || size < 1 || size > maxBoardSize {
+				return o, fmt.Errorf("option %s should be a number between 1 and %d", key, maxBoardSize)
+			}
+			if key == "width" {
+				o.Width = size
+			} else {
+				o.Height = size
+			}
+		case "zombies":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 || n > maxZombies {
+				return o, fmt.Errorf("option zombies should be a number between 1 and %d", maxZombies)
+			}
+			o.Zombies = n
+		case "hp":
+			hp, err := strconv.Atoi(val)
+			if err != nil || hp < 1 || hp > maxHitPoints {
+				return o, fmt.Errorf("option hp should be a number between 1 and %d", maxHitPoints)
+			}
+			o.HitPoints = hp
+		case "tick":
+			tick, err := time.ParseDuration(val)
+			if err != nil || tick < minTick || tick > maxTick {
+				return o, fmt.Errorf("option tick should be a duration between %s and %s", minTick, maxTick)
+			}
+			o.Tick = tick
+		case "seed":
+			seed, err := strconv.ParseInt(val, 10, 64)
+			if err != nil || seed == 0 {
+				return o, fmt.Errorf("option seed should be a non-zero number")
+			}
+			o.Seed = seed
+		case "movement":
+			m, err := ParseMovementKind(val)
+			if err != nil {
+				return o, fmt.Errorf("option %w", err)
+			}
+			o.Movement = m
+		case "types":
+			types := strings.Split(val, ",")
+			for _, t := range types {
+				if t == "" {
+					return o, fmt.Errorf("option types should be a comma separated list of zombie types")
+				}
+			}
+			o.Types = types
+		default:
+			return o, fmt.Errorf("%s is not an option server understands", key)
+		}
+	}
+	return o, nil
+}
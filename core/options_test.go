@@ -0,0 +1,333 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseGameOptions(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    GameOptions
+		wantErr bool
+	}{
+		{
+			name: "no options given, should return defaults",
+			args: args{
+				args: nil,
+			},
+			want:    DefaultGameOptions(),
+			wantErr: false,
+		},
+		{
+			name: "option without a value, should error",
+			args: args{
+				args: []string{"misses="},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown option, should error",
+			args: args{
+				args: []string{"mock=1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown miss mode, should error",
+			args: args{
+				args: []string{"misses=mock"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid miss mode, should not error",
+			args: args{
+				args: []string{"misses=all"},
+			},
+			want: GameOptions{
+				Mode:       GameModeClassic,
+				Tick:       defaultTick,
+				Misses:     MissModeAll,
+				Width:      defaultWidth,
+				Height:     defaultHeight,
+				Zombies:    defaultZombies,
+				Difficulty: DifficultyNormal,
+				HitPoints:  defaultHitPoints,
+			},
+			wantErr: false,
+		},
+		{
+			name: "board size which isn't a number, should error",
+			args: args{
+				args: []string{"width=x"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "board size too big, should error",
+			args: args{
+				args: []string{"height=1001"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown game mode, should error",
+			args: args{
+				args: []string{"mode=mock"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "competitive game mode, should not error",
+			args: args{
+				args: []string{"mode=competitive"},
+			},
+			want: GameOptions{
+				Mode:       GameModeCompetitive,
+				Tick:       defaultTick,
+				Misses:     MissModeShooter,
+				Width:      defaultWidth,
+				Height:     defaultHeight,
+				Zombies:    defaultZombies,
+				Difficulty: DifficultyNormal,
+				HitPoints:  defaultHitPoints,
+			},
+			wantErr: false,
+		},
+		{
+			name: "waves game mode, should not error",
+			args: args{
+				args: []string{"mode=waves"},
+			},
+			want: GameOptions{
+				Mode:       GameModeWaves,
+				Tick:       defaultTick,
+				Misses:     MissModeShooter,
+				Width:      defaultWidth,
+				Height:     defaultHeight,
+				Zombies:    defaultZombies,
+				Difficulty: DifficultyNormal,
+				HitPoints:  defaultHitPoints,
+			},
+			wantErr: false,
+		},
+		{
+			name: "no zombies, should error",
+			args: args{
+				args: []string{"zombies=0"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid zombie count, should not error",
+			args: args{
+				args: []string{"zombies=3"},
+			},
+			want: GameOptions{
+				Mode:       GameModeClassic,
+				Tick:       defaultTick,
+				Misses:     MissModeShooter,
+				Width:      defaultWidth,
+				Height:     defaultHeight,
+				Zombies:    3,
+				Difficulty: DifficultyNormal,
+				HitPoints:  defaultHitPoints,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown difficulty, should error",
+			args: args{
+				args: []string{"difficulty=mock"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "difficulty, should set its preset",
+			args: args{
+				args: []string{"difficulty=nightmare"},
+			},
+			want: GameOptions{
+				Mode:       GameModeClassic,
+				Misses:     MissModeShooter,
+				Difficulty: DifficultyNightmare,
+				Width:      6,
+				Height:     40,
+				Zombies:    6,
+				HitPoints:  5,
+				Tick:       2 * time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "difficulty after overrides, should keep the overrides",
+			args: args{
+				args: []string{"hp=1", "tick=500ms", "difficulty=hard"},
+			},
+			want: GameOptions{
+				Mode:       GameModeClassic,
+				Misses:     MissModeShooter,
+				Difficulty: DifficultyHard,
+				Width:      8,
+				Height:     30,
+				Zombies:    3,
+				HitPoints:  1,
+				Tick:       500 * time.Millisecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "hit points out of range, should error",
+			args: args{
+				args: []string{"hp=0"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tick too fast, should error",
+			args: args{
+				args: []string{"tick=1ms"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tick which isn't a duration, should error",
+			args: args{
+				args: []string{"tick=4"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero seed, should error",
+			args: args{
+				args: []string{"seed=0"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "seed which isn't a number, should error",
+			args: args{
+				args: []string{"seed=x"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid seed, should not error",
+			args: args{
+				args: []string{"seed=-42"},
+			},
+			want: GameOptions{
+				Mode:       GameModeClassic,
+				Tick:       defaultTick,
+				Misses:     MissModeShooter,
+				Width:      defaultWidth,
+				Height:     defaultHeight,
+				Zombies:    defaultZombies,
+				Seed:       -42,
+				Difficulty: DifficultyNormal,
+				HitPoints:  defaultHitPoints,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown movement, should error",
+			args: args{
+				args: []string{"movement=mock"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid movement, should not error",
+			args: args{
+				args: []string{"movement=dodge"},
+			},
+			want: GameOptions{
+				Mode:       GameModeClassic,
+				Tick:       defaultTick,
+				Misses:     MissModeShooter,
+				Width:      defaultWidth,
+				Height:     defaultHeight,
+				Zombies:    defaultZombies,
+				Difficulty: DifficultyNormal,
+				HitPoints:  defaultHitPoints,
+				Movement:   MovementDodge,
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty zombie type, should error",
+			args: args{
+				args: []string{"types=walker,,brute"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zombie types, should not error",
+			args: args{
+				args: []string{"types=walker,brute"},
+			},
+			want: GameOptions{
+				Mode:       GameModeClassic,
+				Tick:       defaultTick,
+				Misses:     MissModeShooter,
+				Width:      defaultWidth,
+				Height:     defaultHeight,
+				Zombies:    defaultZombies,
+				Difficulty: DifficultyNormal,
+				HitPoints:  defaultHitPoints,
+				Types:      []string{"walker", "brute"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid board size, should not error",
+			args: args{
+				args: []string{"width=20", "height=50"},
+			},
+			want: GameOptions{
+				Mode:       GameModeClassic,
+				Tick:       defaultTick,
+				Misses:     MissModeShooter,
+				Width:      20,
+				Height:     50,
+				Zombies:    defaultZombies,
+				Difficulty: DifficultyNormal,
+				HitPoints:  defaultHitPoints,
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGameOptions(tt.args.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseGameOptions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGameOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameOptions_Apply(t *testing.T) {
+	defaults := DefaultGameOptions().WithDifficulty(DifficultyHard)
+	got, err := defaults.Apply([]string{"width=20", "mode=waves"})
+	if err != nil {
+		t.Fatalf("GameOptions.Apply() error = %v", err)
+	}
+
+	want := defaults
+	want.Width = 20
+	want.Mode = GameModeWaves
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GameOptions.Apply() = %v, want %v", got, want)
+	}
+}
@@ -10,6 +10,14 @@ type Player struct {
 	Name     string
 	GameName string
 	Resp     chan Response
+	// Token is a secret the player can use to resume
+	// his session, it's empty if resuming is disabled.
+	Token string
+
+	// Hits and Shots count the shots the
+	// player has made over the whole session.
+	Hits  int
+	Shots int
 }
 
 // NewPlayer returns a new player instance.
@@ -19,3 +27,11 @@ func NewPlayer(name string, sign uid.UUID, resp chan Response) *Player {
 		Resp: resp,
 	}
 }
+
+// Accuracy returns the share of shots which hit a zombie.
+func (p *Player) Accuracy() float64 {
+	if p.Shots == 0 {
+		return 0
+	}
+	return float64(p.Hits) / float64(p.Shots)
+}
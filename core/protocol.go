@@ -0,0 +1,51 @@
+package core
+
+// Capability is a type which describes an optional
+// protocol feature the client and the server can agree on.
+type Capability string
+
+const (
+	// CapabilityJSON is negotiated when the client
+	// wants to use the JSON encoding.
+	CapabilityJSON Capability = "JSON"
+	// CapabilityRequestIDs is negotiated when the client tags its
+	// commands with request IDs, the replies are tagged with them too.
+	CapabilityRequestIDs Capability = "IDS"
+	// CapabilitySpectate is negotiated when the client wants to know
+	// that it can watch games with the SPECTATE command, spectating
+	// doesn't need a handshake so older clients can use it too.
+	CapabilitySpectate Capability = "SPECTATE"
+)
+
+const (
+	// ProtocolVersionLegacy is assumed for clients which don't start
+	// with a handshake, plain-text responses are sent to them in the
+	// formats of the first version, every response on a single line.
+	ProtocolVersionLegacy = 1
+	// ProtocolVersion is the newest version the server speaks.
+	ProtocolVersion = 2
+)
+
+// capabilities lists every capability the server supports.
+var capabilities = []Capability{CapabilityJSON, CapabilityRequestIDs, CapabilitySpectate}
+
+// Negotiate returns the protocol version and capabilities
+// supported by both the client and the server.
+// Capabilities the server doesn't know about are ignored.
+func Negotiate(cmd *CommandHello) (int, []Capability) {
+	version := cmd.Version
+	if version > ProtocolVersion {
+		version = ProtocolVersion
+	}
+
+	caps := make([]Capability, 0, len(capabilities))
+	for _, c := range capabilities {
+		for _, want := range cmd.Capabilities {
+			if c == want {
+				caps = append(caps, c)
+				break
+			}
+		}
+	}
+	return version, caps
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	type args struct {
+		cmd *CommandHello
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantVer  int
+		wantCaps []Capability
+	}{
+		{
+			name: "client is newer than the server, should use server version",
+			args: args{
+				cmd: &CommandHello{
+					Version: ProtocolVersion + 1,
+				},
+			},
+			wantVer:  ProtocolVersion,
+			wantCaps: []Capability{},
+		},
+		{
+			name: "client is older than the server, should use client version",
+			args: args{
+				cmd: &CommandHello{
+					Version: ProtocolVersionLegacy,
+				},
+			},
+			wantVer:  ProtocolVersionLegacy,
+			wantCaps: []Capability{},
+		},
+		{
+			name: "unknown capabilities, should be ignored",
+			args: args{
+				cmd: &CommandHello{
+					Version:      ProtocolVersion,
+					Capabilities: []Capability{"MOCK", CapabilityJSON},
+				},
+			},
+			wantVer:  ProtocolVersion,
+			wantCaps: []Capability{CapabilityJSON},
+		},
+		{
+			name: "every capability, should be negotiated in server order",
+			args: args{
+				cmd: &CommandHello{
+					Version:      ProtocolVersion,
+					Capabilities: []Capability{CapabilitySpectate, CapabilityRequestIDs, CapabilityJSON},
+				},
+			},
+			wantVer:  ProtocolVersion,
+			wantCaps: []Capability{CapabilityJSON, CapabilityRequestIDs, CapabilitySpectate},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotVer, gotCaps := Negotiate(tt.args.cmd)
+			if gotVer != tt.wantVer {
+				t.Errorf("Negotiate() version = %v, want %v", gotVer, tt.wantVer)
+			}
+			if !reflect.DeepEqual(gotCaps, tt.wantCaps) {
+				t.Errorf("Negotiate() capabilities = %v, want %v", gotCaps, tt.wantCaps)
+			}
+		})
+	}
+}
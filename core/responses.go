@@ -1,27 +1,144 @@
 package core
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ResponseHello is sent back to the client
+// when the protocol handshake is completed.
+type ResponseHello struct {
+	version      int
+	capabilities []Capability
+}
+
+// ResponseWelcome is sent back to the client
+// when he joins the server as a player.
+type ResponseWelcome struct {
+	player string
+	// token can be used to resume the session
+	// after the connection drops, it's empty
+	// when the server doesn't allow resuming.
+	token string
+}
+
+// ResponseRegistered is sent back to the client
+// when he registers a player name.
+type ResponseRegistered struct {
+	player string
+}
+
+// ResponseResumed is sent back to the client when he resumes
+// a session, it's followed by the responses he has missed.
+type ResponseResumed struct {
+	player string
+	missed int
+}
+
+// ResponseJoined is sent back to the client when he joins
+// a game, it carries a snapshot of the game.
+type ResponseJoined struct {
+	game    string
+	players []string
+	zombies []ZombieInfo
+}
+
+// ResponseLeft is sent to the clients in a game
+// when a player leaves it.
+type ResponseLeft struct {
+	player string
+}
+
+// ZombieInfo describes the state of a
+// single zombie as it's shown to the clients.
+type ZombieInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+	Hits int    `json:"hits"`
+}
+
+// GameInfo describes the state of a
+// single game as it's shown to the clients.
+// Seed can be used to create a game which
+// plays out the same way.
+type GameInfo struct {
+	Name    string       `json:"name"`
+	Mode    GameMode     `json:"mode"`
+	Width   int          `json:"width"`
+	Height  int          `json:"height"`
+	Seed    int64        `json:"seed"`
+	Players []string     `json:"players"`
+	Zombies []ZombieInfo `json:"zombies"`
+}
+
+// ResponseGames is sent back to the client
+// when he asks for the list of active games.
+type ResponseGames struct {
+	games []GameInfo
+}
+
+// ResponseGameInfo is sent back to the client
+// when he asks for the state of a single game.
+type ResponseGameInfo struct {
+	info GameInfo
+}
 
 // ResponseBoom is sent back to the client
 // if he hits a shot.
 type ResponseBoom struct {
+	player    string
+	hits      int
+	enemy     string
+	enemyType string
+}
+
+// ResponseMiss is sent back to the client
+// if a shot misses.
+type ResponseMiss struct {
 	player string
-	hits   int
-	enemy  string
+	x      int
+	y      int
 }
 
 // ResponseWalk is sent to the client
 // when a position of an enemy changes.
 type ResponseWalk struct {
-	enemy string
-	x     int
-	y     int
+	enemy     string
+	enemyType string
+	x         int
+	y         int
+}
+
+// ResponseWave is sent to the client when
+// a new wave of zombies comes in the waves game mode.
+type ResponseWave struct {
+	wave int
+}
+
+// ResponseScore is sent to the clients in a game right
+// before it finishes, it lists how every player did.
+type ResponseScore struct {
+	scores []Score
+}
+
+// ResponseLeaderboard is sent back to the client
+// when he asks for the best players of the server.
+type ResponseLeaderboard struct {
+	standings []Standing
 }
 
 // ResponseFinish is sent back to the client
 // when a game he is in ends.
 type ResponseFinish struct {
 	won bool
+	// score is set only in the waves game mode,
+	// it's the number of waves the team has cleared.
+	score *int
+	// winner is set only for the winner
+	// of the competitive game mode.
+	winner string
 }
 
 // ResponseError is sent to the client when his request
@@ -35,12 +152,56 @@ type ResponseError struct {
 type ResponseType string
 
 const (
+	// ResponseTypeHello is returned by the server to the client
+	// with the negotiated protocol version and capabilities.
+	ResponseTypeHello ResponseType = "HELLO"
+	// ResponseTypeWelcome is returned by the server to the client
+	// when he joins the server.
+	ResponseTypeWelcome ResponseType = "WELCOME"
+	// ResponseTypeRegistered is returned by the server to the client
+	// when he registers a player name.
+	ResponseTypeRegistered ResponseType = "REGISTERED"
+	// ResponseTypeResumed is returned by the server to the client
+	// when he resumes a session after a dropped connection.
+	ResponseTypeResumed ResponseType = "RESUMED"
+	// ResponseTypeJoined is returned by the server to the client
+	// when he joins a game.
+	ResponseTypeJoined ResponseType = "JOINED"
+	// ResponseTypeLeft is streamed by the server to the clients
+	// in a game when a player leaves it.
+	ResponseTypeLeft ResponseType = "LEFT"
+	// ResponseTypeGames is returned by the server to the client
+	// with a list of active games.
+	ResponseTypeGames ResponseType = "GAMES"
+	// ResponseTypeGame is a single game in the list of active games.
+	ResponseTypeGame ResponseType = "GAME"
+	// ResponseTypeGameInfo is returned by the server to the client
+	// with the state of a single game.
+	ResponseTypeGameInfo ResponseType = "GAMEINFO"
+	// ResponseTypeZombie is a single zombie in a snapshot of a game.
+	ResponseTypeZombie ResponseType = "ZOMBIE"
 	// ResponseTypeWalk is expected to be streamed by the server
 	// when a zombie moves.
 	ResponseTypeWalk ResponseType = "WALK"
 	// ResponseTypeBoom is expected to be streamed by the server
 	// when a zombie is hit.
 	ResponseTypeBoom ResponseType = "BOOM"
+	// ResponseTypeMiss is expected to be streamed by the server
+	// when a shot misses.
+	ResponseTypeMiss ResponseType = "MISS"
+	// ResponseTypeWave is streamed by the server when a new
+	// wave of zombies comes in the waves game mode.
+	ResponseTypeWave ResponseType = "WAVE"
+	// ResponseTypeScore is streamed by the server
+	// right before a game finishes.
+	ResponseTypeScore ResponseType = "SCORE"
+	// ResponseTypePlayer is a single player in a scoreboard.
+	ResponseTypePlayer ResponseType = "PLAYER"
+	// ResponseTypeLeaderboard is returned by the server to the client
+	// when he asks for the best players.
+	ResponseTypeLeaderboard ResponseType = "LEADERBOARD"
+	// ResponseTypeRank is a single player in a leaderboard.
+	ResponseTypeRank ResponseType = "RANK"
 	// ResponseError is returned by the server to the client incase
 	// of an error.
 	ResponseTypeError ResponseType = "ERROR"
@@ -55,32 +216,347 @@ const (
 type Response interface {
 	// String will convert a response in to a string.
 	String() string
+	// MarshalJSON will convert a response in to a JSON object.
+	MarshalJSON() ([]byte, error)
 }
 
-func NewResponseBoom(player, enemy string, hits int) *ResponseBoom {
-	return &ResponseBoom{
+// legacyResponse is a response whose plain-text format has changed
+// since the legacy protocol version, or which takes more than a line.
+type legacyResponse interface {
+	// LegacyString converts a response in to a single line sent to
+	// the legacy clients, they aren't sent the response if it's empty.
+	LegacyString() string
+}
+
+func NewResponseHello(version int, capabilities []Capability) *ResponseHello {
+	return &ResponseHello{
+		version:      version,
+		capabilities: capabilities,
+	}
+}
+
+func (r *ResponseHello) String() string {
+	s := fmt.Sprintf("%s %d", ResponseTypeHello, r.version)
+	for _, c := range r.capabilities {
+		s += " " + string(c)
+	}
+	return s
+}
+
+func (r *ResponseHello) MarshalJSON() ([]byte, error) {
+	caps := r.capabilities
+	if caps == nil {
+		caps = []Capability{}
+	}
+	return json.Marshal(struct {
+		Type         ResponseType `json:"type"`
+		Version      int          `json:"version"`
+		Capabilities []Capability `json:"capabilities"`
+	}{ResponseTypeHello, r.version, caps})
+}
+
+func NewResponseWelcome(player, token string) *ResponseWelcome {
+	return &ResponseWelcome{
+		player: player,
+		token:  token,
+	}
+}
+
+func (r *ResponseWelcome) String() string {
+	if r.token != "" {
+		return fmt.Sprintf("%s %s %s", ResponseTypeWelcome, r.player, r.token)
+	}
+	return fmt.Sprintf("%s %s", ResponseTypeWelcome, r.player)
+}
+
+func (r *ResponseWelcome) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   ResponseType `json:"type"`
+		Player string       `json:"player"`
+		Token  string       `json:"token,omitempty"`
+	}{ResponseTypeWelcome, r.player, r.token})
+}
+
+func NewResponseRegistered(player string) *ResponseRegistered {
+	return &ResponseRegistered{
+		player: player,
+	}
+}
+
+func (r *ResponseRegistered) String() string {
+	return fmt.Sprintf("%s %s", ResponseTypeRegistered, r.player)
+}
+
+func (r *ResponseRegistered) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   ResponseType `json:"type"`
+		Player string       `json:"player"`
+	}{ResponseTypeRegistered, r.player})
+}
+
+func NewResponseResumed(player string, missed int) *ResponseResumed {
+	return &ResponseResumed{
 		player: player,
-		enemy:  enemy,
-		hits:   hits,
+		missed: missed,
+	}
+}
+
+func (r *ResponseResumed) String() string {
+	return fmt.Sprintf("%s %s %d", ResponseTypeResumed, r.player, r.missed)
+}
+
+func (r *ResponseResumed) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   ResponseType `json:"type"`
+		Player string       `json:"player"`
+		Missed int          `json:"missed"`
+	}{ResponseTypeResumed, r.player, r.missed})
+}
+
+func NewResponseJoined(game string, players []string, zombies []ZombieInfo) *ResponseJoined {
+	return &ResponseJoined{
+		game:    game,
+		players: players,
+		zombies: zombies,
+	}
+}
+
+// String puts the zombie count in the header and every zombie
+// on its own line after it, so text clients know how many follow.
+func (r *ResponseJoined) String() string {
+	s := fmt.Sprintf("%s %s %d", ResponseTypeJoined, r.game, len(r.zombies))
+	for _, p := range r.players {
+		s += " " + p
+	}
+	return s + zombieLines(r.zombies)
+}
+
+// LegacyString puts the zombies on the same line after
+// the players, each of them as `{zombie} {x} {y}`.
+func (r *ResponseJoined) LegacyString() string {
+	s := fmt.Sprintf("%s %s", ResponseTypeJoined, r.game)
+	for _, p := range r.players {
+		s += " " + p
+	}
+	for _, z := range r.zombies {
+		s += fmt.Sprintf(" %s %d %d", z.Name, z.X, z.Y)
+	}
+	return s
+}
+
+func (r *ResponseJoined) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type    ResponseType `json:"type"`
+		Game    string       `json:"game"`
+		Players []string     `json:"players"`
+		Zombies []ZombieInfo `json:"zombies"`
+	}{ResponseTypeJoined, r.game, nonNilStrings(r.players), nonNilZombies(r.zombies)})
+}
+
+func NewResponseLeft(player string) *ResponseLeft {
+	return &ResponseLeft{
+		player: player,
+	}
+}
+
+func (r *ResponseLeft) String() string {
+	return fmt.Sprintf("%s %s", ResponseTypeLeft, r.player)
+}
+
+func (r *ResponseLeft) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   ResponseType `json:"type"`
+		Player string       `json:"player"`
+	}{ResponseTypeLeft, r.player})
+}
+
+func NewResponseGames(games []GameInfo) *ResponseGames {
+	return &ResponseGames{
+		games: games,
+	}
+}
+
+// String puts every game and its zombies on their
+// own lines after the header, so it's readable for text clients.
+func (r *ResponseGames) String() string {
+	s := fmt.Sprintf("%s %d", ResponseTypeGames, len(r.games))
+	for _, g := range r.games {
+		s += fmt.Sprintf("\n%s %s %s %d", ResponseTypeGame, g.Name, g.Mode, len(g.Players))
+		s += zombieLines(g.Zombies)
+	}
+	return s
+}
+
+// LegacyString lists only the names of the games.
+func (r *ResponseGames) LegacyString() string {
+	s := fmt.Sprintf("%s %d", ResponseTypeGames, len(r.games))
+	for _, g := range r.games {
+		s += " " + g.Name
+	}
+	return s
+}
+
+func (r *ResponseGames) MarshalJSON() ([]byte, error) {
+	type game struct {
+		Name    string       `json:"name"`
+		Mode    GameMode     `json:"mode"`
+		Players int          `json:"players"`
+		Zombies []ZombieInfo `json:"zombies"`
+	}
+	games := make([]game, 0, len(r.games))
+	for _, g := range r.games {
+		games = append(games, game{g.Name, g.Mode, len(g.Players), nonNilZombies(g.Zombies)})
+	}
+	return json.Marshal(struct {
+		Type  ResponseType `json:"type"`
+		Games []game       `json:"games"`
+	}{ResponseTypeGames, games})
+}
+
+func NewResponseGameInfo(info GameInfo) *ResponseGameInfo {
+	return &ResponseGameInfo{
+		info: info,
+	}
+}
+
+func (r *ResponseGameInfo) String() string {
+	i := r.info
+	s := fmt.Sprintf("%s %s %s %d %d %d", ResponseTypeGameInfo, i.Name, i.Mode, i.Width, i.Height, i.Seed)
+	for _, p := range i.Players {
+		s += " " + p
+	}
+	return s + zombieLines(i.Zombies)
+}
+
+// LegacyString leaves out the seed and the zombies.
+func (r *ResponseGameInfo) LegacyString() string {
+	i := r.info
+	s := fmt.Sprintf("%s %s %s %d %d", ResponseTypeGameInfo, i.Name, i.Mode, i.Width, i.Height)
+	for _, p := range i.Players {
+		s += " " + p
+	}
+	return s
+}
+
+func (r *ResponseGameInfo) MarshalJSON() ([]byte, error) {
+	i := r.info
+	return json.Marshal(struct {
+		Type    ResponseType `json:"type"`
+		Name    string       `json:"name"`
+		Mode    GameMode     `json:"mode"`
+		Width   int          `json:"width"`
+		Height  int          `json:"height"`
+		Seed    int64        `json:"seed"`
+		Players []string     `json:"players"`
+		Zombies []ZombieInfo `json:"zombies"`
+	}{ResponseTypeGameInfo, i.Name, i.Mode, i.Width, i.Height, i.Seed, nonNilStrings(i.Players), nonNilZombies(i.Zombies)})
+}
+
+// zombieLines puts every zombie on its own line,
+// it's used by responses which carry a snapshot of a game.
+func zombieLines(zombies []ZombieInfo) string {
+	s := ""
+	for _, z := range zombies {
+		s += fmt.Sprintf("\n%s %s %d %d %d %s", ResponseTypeZombie, z.Name, z.X, z.Y, z.Hits, z.Type)
+	}
+	return s
+}
+
+// nonNilStrings makes sure an empty list is
+// encoded as an empty JSON array, not null.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// nonNilZombies makes sure an empty list is
+// encoded as an empty JSON array, not null.
+func nonNilZombies(z []ZombieInfo) []ZombieInfo {
+	if z == nil {
+		return []ZombieInfo{}
+	}
+	return z
+}
+
+func NewResponseBoom(player, enemy, enemyType string, hits int) *ResponseBoom {
+	return &ResponseBoom{
+		player:    player,
+		enemy:     enemy,
+		enemyType: enemyType,
+		hits:      hits,
 	}
 }
 
 func (r *ResponseBoom) String() string {
+	return fmt.Sprintf("%s %s %d %s %s", ResponseTypeBoom, r.player, r.hits, r.enemy, r.enemyType)
+}
+
+// LegacyString leaves out the zombie type.
+func (r *ResponseBoom) LegacyString() string {
 	return fmt.Sprintf("%s %s %d %s", ResponseTypeBoom, r.player, r.hits, r.enemy)
 }
 
-func NewResponseWalk(enemy string, x, y int) *ResponseWalk {
+func (r *ResponseBoom) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type      ResponseType `json:"type"`
+		Player    string       `json:"player"`
+		Hits      int          `json:"hits"`
+		Enemy     string       `json:"enemy"`
+		EnemyType string       `json:"enemyType"`
+	}{ResponseTypeBoom, r.player, r.hits, r.enemy, r.enemyType})
+}
+
+func NewResponseMiss(player string, x, y int) *ResponseMiss {
+	return &ResponseMiss{
+		player: player,
+		x:      x,
+		y:      y,
+	}
+}
+
+func (r *ResponseMiss) String() string {
+	return fmt.Sprintf("%s %s %d %d", ResponseTypeMiss, r.player, r.x, r.y)
+}
+
+func (r *ResponseMiss) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   ResponseType `json:"type"`
+		Player string       `json:"player"`
+		X      int          `json:"x"`
+		Y      int          `json:"y"`
+	}{ResponseTypeMiss, r.player, r.x, r.y})
+}
+
+func NewResponseWalk(enemy, enemyType string, x, y int) *ResponseWalk {
 	return &ResponseWalk{
-		enemy: enemy,
-		x:     x,
-		y:     y,
+		enemy:     enemy,
+		enemyType: enemyType,
+		x:         x,
+		y:         y,
 	}
 }
 
 func (r *ResponseWalk) String() string {
+	return fmt.Sprintf("%s %s %d %d %s", ResponseTypeWalk, r.enemy, r.x, r.y, r.enemyType)
+}
+
+// LegacyString leaves out the zombie type.
+func (r *ResponseWalk) LegacyString() string {
 	return fmt.Sprintf("%s %s %d %d", ResponseTypeWalk, r.enemy, r.x, r.y)
 }
 
+func (r *ResponseWalk) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type      ResponseType `json:"type"`
+		Enemy     string       `json:"enemy"`
+		X         int          `json:"x"`
+		Y         int          `json:"y"`
+		EnemyType string       `json:"enemyType"`
+	}{ResponseTypeWalk, r.enemy, r.x, r.y, r.enemyType})
+}
+
 func NewResponseError(err error) *ResponseError {
 	return &ResponseError{
 		err: err,
@@ -91,17 +567,173 @@ func (r *ResponseError) String() string {
 	return fmt.Sprintf("%s %v", ResponseTypeError, r.err)
 }
 
+func (r *ResponseError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  ResponseType `json:"type"`
+		Error string       `json:"error"`
+	}{ResponseTypeError, fmt.Sprint(r.err)})
+}
+
+func NewResponseWave(wave int) *ResponseWave {
+	return &ResponseWave{
+		wave: wave,
+	}
+}
+
+func (r *ResponseWave) String() string {
+	return fmt.Sprintf("%s %d", ResponseTypeWave, r.wave)
+}
+
+func (r *ResponseWave) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type ResponseType `json:"type"`
+		Wave int          `json:"wave"`
+	}{ResponseTypeWave, r.wave})
+}
+
+func NewResponseScore(scores []Score) *ResponseScore {
+	return &ResponseScore{
+		scores: scores,
+	}
+}
+
+// String puts every player on his own line after the header,
+// reaction time is given in milliseconds.
+func (r *ResponseScore) String() string {
+	s := fmt.Sprintf("%s %d", ResponseTypeScore, len(r.scores))
+	for _, sc := range r.scores {
+		s += fmt.Sprintf("\n%s %s %d %d %d %d", ResponseTypePlayer,
+			sc.Player, sc.Hits, sc.Misses, sc.Kills, sc.Reaction.Milliseconds())
+	}
+	return s
+}
+
+// LegacyString is empty, the scoreboard isn't sent to legacy clients.
+func (r *ResponseScore) LegacyString() string {
+	return ""
+}
+
+func (r *ResponseScore) MarshalJSON() ([]byte, error) {
+	type player struct {
+		Name       string `json:"name"`
+		Hits       int    `json:"hits"`
+		Misses     int    `json:"misses"`
+		Kills      int    `json:"kills"`
+		ReactionMs int64  `json:"reactionMs"`
+	}
+	players := make([]player, 0, len(r.scores))
+	for _, sc := range r.scores {
+		players = append(players, player{sc.Player, sc.Hits, sc.Misses, sc.Kills, sc.Reaction.Milliseconds()})
+	}
+	return json.Marshal(struct {
+		Type    ResponseType `json:"type"`
+		Players []player     `json:"players"`
+	}{ResponseTypeScore, players})
+}
+
+func NewResponseLeaderboard(standings []Standing) *ResponseLeaderboard {
+	return &ResponseLeaderboard{
+		standings: standings,
+	}
+}
+
+// String puts every player on his own line after the header,
+// ranked from the best one.
+func (r *ResponseLeaderboard) String() string {
+	s := fmt.Sprintf("%s %d", ResponseTypeLeaderboard, len(r.standings))
+	for i, st := range r.standings {
+		s += fmt.Sprintf("\n%s %d %s %d %d %d %.2f", ResponseTypeRank,
+			i+1, st.Player, st.Wins, st.Losses, st.Kills, st.Accuracy())
+	}
+	return s
+}
+
+// LegacyString lists only the names of the players, best one first.
+func (r *ResponseLeaderboard) LegacyString() string {
+	s := fmt.Sprintf("%s %d", ResponseTypeLeaderboard, len(r.standings))
+	for _, st := range r.standings {
+		s += " " + st.Player
+	}
+	return s
+}
+
+func (r *ResponseLeaderboard) MarshalJSON() ([]byte, error) {
+	type player struct {
+		Rank     int     `json:"rank"`
+		Name     string  `json:"name"`
+		Wins     int     `json:"wins"`
+		Losses   int     `json:"losses"`
+		Kills    int     `json:"kills"`
+		Accuracy float64 `json:"accuracy"`
+	}
+	players := make([]player, 0, len(r.standings))
+	for i, st := range r.standings {
+		players = append(players, player{i + 1, st.Player, st.Wins, st.Losses, st.Kills, st.Accuracy()})
+	}
+	return json.Marshal(struct {
+		Type    ResponseType `json:"type"`
+		Players []player     `json:"players"`
+	}{ResponseTypeLeaderboard, players})
+}
+
 func NewResponseFinish(won bool) *ResponseFinish {
 	return &ResponseFinish{
 		won: won,
 	}
 }
 
+// NewResponseFinishWaves returns a response for the end of a waves
+// game mode, the game is always lost but the team has a score.
+func NewResponseFinishWaves(cleared int) *ResponseFinish {
+	return &ResponseFinish{
+		won:   false,
+		score: &cleared,
+	}
+}
+
+// NewResponseFinishWinner returns a response for the
+// winner of a competitive game, the rest of the players lose.
+func NewResponseFinishWinner(player string) *ResponseFinish {
+	return &ResponseFinish{
+		won:    true,
+		winner: player,
+	}
+}
+
 func (r *ResponseFinish) String() string {
-	result := "LOST"
+	switch {
+	case r.score != nil:
+		return fmt.Sprintf("%s %s %d", ResponseTypeFinish, r.result(), *r.score)
+	case r.winner != "":
+		return fmt.Sprintf("%s %s %s", ResponseTypeFinish, r.result(), r.winner)
+	}
+	return fmt.Sprintf("%s %s", ResponseTypeFinish, r.result())
+}
+
+// LegacyString is either `FINISH WON` or `FINISH LOST`,
+// the winner of a competitive game has won it.
+func (r *ResponseFinish) LegacyString() string {
 	if r.won {
-		result = "WON"
+		return fmt.Sprintf("%s WON", ResponseTypeFinish)
 	}
+	return fmt.Sprintf("%s LOST", ResponseTypeFinish)
+}
 
-	return fmt.Sprintf("%s %s", ResponseTypeFinish, result)
+func (r *ResponseFinish) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   ResponseType `json:"type"`
+		Result string       `json:"result"`
+		Score  *int         `json:"score,omitempty"`
+		Player string       `json:"player,omitempty"`
+	}{ResponseTypeFinish, r.result(), r.score, r.winner})
+}
+
+func (r *ResponseFinish) result() string {
+	switch {
+	case r.winner != "":
+		return "WINNER"
+	case r.won:
+		return "WON"
+	}
+	return "LOST"
 }
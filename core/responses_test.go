@@ -4,16 +4,160 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 )
 
+func TestResponseHello_String(t *testing.T) {
+	type fields struct {
+		version      int
+		capabilities []Capability
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name: "to string ResponseHello without capabilities",
+			fields: fields{
+				version: ProtocolVersion,
+			},
+			want: fmt.Sprintf("%s %d", ResponseTypeHello, ProtocolVersion),
+		},
+		{
+			name: "to string ResponseHello with capabilities",
+			fields: fields{
+				version:      ProtocolVersion,
+				capabilities: []Capability{CapabilityJSON},
+			},
+			want: fmt.Sprintf("%s %d %s", ResponseTypeHello, ProtocolVersion, CapabilityJSON),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResponseHello{
+				version:      tt.fields.version,
+				capabilities: tt.fields.capabilities,
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("ResponseHello.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseJoined_String(t *testing.T) {
+	type fields struct {
+		game    string
+		players []string
+		zombies []ZombieInfo
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name: "to string ResponseJoined",
+			fields: fields{
+				game:    "A",
+				players: []string{"B", "C"},
+				zombies: []ZombieInfo{{Name: "D", Type: "walker", X: 1, Y: 2}, {Name: "E", Type: "brute", X: 3, Y: 4, Hits: 1}},
+			},
+			want: fmt.Sprintf("%s A 2 B C\n%s D 1 2 0 walker\n%s E 3 4 1 brute", ResponseTypeJoined, ResponseTypeZombie, ResponseTypeZombie),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResponseJoined{
+				game:    tt.fields.game,
+				players: tt.fields.players,
+				zombies: tt.fields.zombies,
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("ResponseJoined.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseGames_String(t *testing.T) {
+	type fields struct {
+		games []GameInfo
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name: "to string ResponseGames without games",
+			fields: fields{
+				games: nil,
+			},
+			want: fmt.Sprintf("%s 0", ResponseTypeGames),
+		},
+		{
+			name: "to string ResponseGames with games",
+			fields: fields{
+				games: []GameInfo{
+					{Name: "A", Mode: GameModeClassic, Players: []string{"B", "C"}, Zombies: []ZombieInfo{{Name: "D", Type: "walker", X: 1, Y: 2, Hits: 3}}},
+					{Name: "E", Mode: GameModeWaves},
+				},
+			},
+			want: fmt.Sprintf("%s 2\n%s A classic 2\n%s D 1 2 3 walker\n%s E waves 0", ResponseTypeGames, ResponseTypeGame, ResponseTypeZombie, ResponseTypeGame),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResponseGames{
+				games: tt.fields.games,
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("ResponseGames.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseGameInfo_String(t *testing.T) {
+	type fields struct {
+		info GameInfo
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name: "to string ResponseGameInfo",
+			fields: fields{
+				info: GameInfo{Name: "A", Mode: GameModeCompetitive, Width: 10, Height: 30, Seed: 42, Players: []string{"B", "C"}, Zombies: []ZombieInfo{{Name: "D", Type: "walker", X: 1, Y: 2, Hits: 3}}},
+			},
+			want: fmt.Sprintf("%s A competitive 10 30 42 B C\n%s D 1 2 3 walker", ResponseTypeGameInfo, ResponseTypeZombie),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResponseGameInfo{
+				info: tt.fields.info,
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("ResponseGameInfo.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestResponseBoom_String(t *testing.T) {
 	mockStr1 := "A"
 	mockStr2 := "B"
 	mockInt1 := 1
 	type fields struct {
-		player string
-		hits   int
-		enemy  string
+		player    string
+		hits      int
+		enemy     string
+		enemyType string
 	}
 	tests := []struct {
 		name   string
@@ -23,19 +167,21 @@ func TestResponseBoom_String(t *testing.T) {
 		{
 			name: "to string ResponseBoom",
 			fields: fields{
-				player: mockStr1,
-				hits:   mockInt1,
-				enemy:  mockStr2,
+				player:    mockStr1,
+				hits:      mockInt1,
+				enemy:     mockStr2,
+				enemyType: "walker",
 			},
-			want: fmt.Sprintf("%s %s %d %s", ResponseTypeBoom, mockStr1, mockInt1, mockStr2),
+			want: fmt.Sprintf("%s %s %d %s walker", ResponseTypeBoom, mockStr1, mockInt1, mockStr2),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &ResponseBoom{
-				player: tt.fields.player,
-				hits:   tt.fields.hits,
-				enemy:  tt.fields.enemy,
+				player:    tt.fields.player,
+				hits:      tt.fields.hits,
+				enemy:     tt.fields.enemy,
+				enemyType: tt.fields.enemyType,
 			}
 			if got := r.String(); got != tt.want {
 				t.Errorf("ResponseBoom.String() = %v, want %v", got, tt.want)
@@ -44,14 +190,163 @@ func TestResponseBoom_String(t *testing.T) {
 	}
 }
 
+func TestResponseMiss_String(t *testing.T) {
+	type fields struct {
+		player string
+		x      int
+		y      int
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name: "to string ResponseMiss",
+			fields: fields{
+				player: "A",
+				x:      1,
+				y:      2,
+			},
+			want: fmt.Sprintf("%s A 1 2", ResponseTypeMiss),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResponseMiss{
+				player: tt.fields.player,
+				x:      tt.fields.x,
+				y:      tt.fields.y,
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("ResponseMiss.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseScore_String(t *testing.T) {
+	type fields struct {
+		scores []Score
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name:   "to string ResponseScore without players",
+			fields: fields{},
+			want:   fmt.Sprintf("%s 0", ResponseTypeScore),
+		},
+		{
+			name: "to string ResponseScore",
+			fields: fields{
+				scores: []Score{
+					{Player: "A", Hits: 3, Misses: 1, Kills: 1, Reaction: 1500 * time.Millisecond},
+					{Player: "B"},
+				},
+			},
+			want: fmt.Sprintf("%s 2\n%s A 3 1 1 1500\n%s B 0 0 0 0", ResponseTypeScore, ResponseTypePlayer, ResponseTypePlayer),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResponseScore{
+				scores: tt.fields.scores,
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("ResponseScore.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseWelcome_String(t *testing.T) {
+	type fields struct {
+		player string
+		token  string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name: "to string ResponseWelcome without token",
+			fields: fields{
+				player: "A",
+			},
+			want: fmt.Sprintf("%s A", ResponseTypeWelcome),
+		},
+		{
+			name: "to string ResponseWelcome with token",
+			fields: fields{
+				player: "A",
+				token:  "B",
+			},
+			want: fmt.Sprintf("%s A B", ResponseTypeWelcome),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResponseWelcome{
+				player: tt.fields.player,
+				token:  tt.fields.token,
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("ResponseWelcome.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseLeaderboard_String(t *testing.T) {
+	type fields struct {
+		standings []Standing
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   string
+	}{
+		{
+			name:   "to string ResponseLeaderboard without players",
+			fields: fields{},
+			want:   fmt.Sprintf("%s 0", ResponseTypeLeaderboard),
+		},
+		{
+			name: "to string ResponseLeaderboard",
+			fields: fields{
+				standings: []Standing{
+					{Player: "A", Wins: 2, Losses: 1, Kills: 3, Hits: 3, Shots: 4},
+					{Player: "B", Losses: 1},
+				},
+			},
+			want: fmt.Sprintf("%s 2\n%s 1 A 2 1 3 0.75\n%s 2 B 0 1 0 0.00", ResponseTypeLeaderboard, ResponseTypeRank, ResponseTypeRank),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ResponseLeaderboard{
+				standings: tt.fields.standings,
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("ResponseLeaderboard.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestResponseWalk_String(t *testing.T) {
 	mockStr1 := "A"
 	mockInt1 := 1
 	mockInt2 := 2
 	type fields struct {
-		enemy string
-		x     int
-		y     int
+		enemy     string
+		enemyType string
+		x         int
+		y         int
 	}
 	tests := []struct {
 		name   string
@@ -61,19 +356,21 @@ func TestResponseWalk_String(t *testing.T) {
 		{
 			name: "to string ResponseWalk",
 			fields: fields{
-				enemy: mockStr1,
-				x:     mockInt1,
-				y:     mockInt2,
+				enemy:     mockStr1,
+				enemyType: "runner",
+				x:         mockInt1,
+				y:         mockInt2,
 			},
-			want: fmt.Sprintf("%s %s %d %d", ResponseTypeWalk, mockStr1, mockInt1, mockInt2),
+			want: fmt.Sprintf("%s %s %d %d runner", ResponseTypeWalk, mockStr1, mockInt1, mockInt2),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &ResponseWalk{
-				enemy: tt.fields.enemy,
-				x:     tt.fields.x,
-				y:     tt.fields.y,
+				enemy:     tt.fields.enemy,
+				enemyType: tt.fields.enemyType,
+				x:         tt.fields.x,
+				y:         tt.fields.y,
 			}
 			if got := r.String(); got != tt.want {
 				t.Errorf("ResponseWalk.String() = %v, want %v", got, tt.want)
@@ -115,8 +412,12 @@ func TestResponseError_String(t *testing.T) {
 func TestResponseFinish_String(t *testing.T) {
 	mockBoolt := true
 	mockBoolf := false
+	mockScore := 2
+	mockWinner := "A"
 	type fields struct {
-		won bool
+		won    bool
+		score  *int
+		winner string
 	}
 	tests := []struct {
 		name   string
@@ -137,11 +438,29 @@ func TestResponseFinish_String(t *testing.T) {
 			},
 			want: fmt.Sprintf("%s %s", ResponseTypeFinish, "LOST"),
 		},
+		{
+			name: "to string ResponseFinish lost with score",
+			fields: fields{
+				won:   mockBoolf,
+				score: &mockScore,
+			},
+			want: fmt.Sprintf("%s %s %d", ResponseTypeFinish, "LOST", mockScore),
+		},
+		{
+			name: "to string ResponseFinish winner",
+			fields: fields{
+				won:    mockBoolt,
+				winner: mockWinner,
+			},
+			want: fmt.Sprintf("%s %s %s", ResponseTypeFinish, "WINNER", mockWinner),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &ResponseFinish{
-				won: tt.fields.won,
+				won:    tt.fields.won,
+				score:  tt.fields.score,
+				winner: tt.fields.winner,
 			}
 			if got := r.String(); got != tt.want {
 				t.Errorf("ResponseFinish.String() = %v, want %v", got, tt.want)
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"sort"
+	"time"
+)
+
+// Score describes how a single player did in a game.
+type Score struct {
+	Player string
+	// Hits and Misses count the shots, a shot
+	// hitting multiple zombies is a single hit.
+	Hits   int
+	Misses int
+	// Kills is the number of killing blows.
+	Kills int
+	// Reaction is the average time it took the player
+	// to shoot since the zombies last walked.
+	Reaction time.Duration
+}
+
+// Scoreboard tracks the scores of every player in a single game.
+type Scoreboard struct {
+	scores map[string]*Score
+	// reactions is the sum of every players reaction times.
+	reactions map[string]time.Duration
+}
+
+// NewScoreboard returns an empty scoreboard.
+func NewScoreboard() *Scoreboard {
+	return &Scoreboard{
+		scores:    make(map[string]*Score),
+		reactions: make(map[string]time.Duration),
+	}
+}
+
+// Join adds a player to the scoreboard, so he is
+// listed even if he never takes a shot.
+func (s *Scoreboard) Join(player string) {
+	if _, ok := s.scores[player]; ok {
+		return
+	}
+	s.scores[player] = &Score{Player: player}
+}
+
+// Shot records a shot of the player.
+func (s *Scoreboard) Shot(player string, hit bool, kills int, reaction time.Duration) {
+	s.Join(player)
+	sc := s.scores[player]
+	if hit {
+		sc.Hits++
+	} else {
+		sc.Misses++
+	}
+	sc.Kills += kills
+
+	s.reactions[player] += reaction
+	sc.Reaction = s.reactions[player] / time.Duration(sc.Hits+sc.Misses)
+}
+
+// Scores returns the score of every player, best players first.
+// Players are ranked by kills, then hits and then by name.
+func (s *Scoreboard) Scores() []Score {
+	scores := make([]Score, 0, len(s.scores))
+	for _, sc := range s.scores {
+		scores = append(scores, *sc)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		a, b := scores[i], scores[j]
+		if a.Kills != b.Kills {
+			return a.Kills > b.Kills
+		}
+		if a.Hits != b.Hits {
+			return a.Hits > b.Hits
+		}
+		return a.Player < b.Player
+	})
+	return scores
+}
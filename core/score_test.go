@@ -0,0 +1,85 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScoreboard_Scores(t *testing.T) {
+	type shot struct {
+		player   string
+		hit      bool
+		kills    int
+		reaction time.Duration
+	}
+	type args struct {
+		joined []string
+		shots  []shot
+	}
+	tests := []struct {
+		name string
+		args args
+		want []Score
+	}{
+		{
+			name: "no players, should be empty",
+			args: args{},
+			want: []Score{},
+		},
+		{
+			name: "player without shots, should be listed",
+			args: args{
+				joined: []string{"A"},
+			},
+			want: []Score{
+				{Player: "A"},
+			},
+		},
+		{
+			name: "multiple shots, should average the reaction time",
+			args: args{
+				joined: []string{"A"},
+				shots: []shot{
+					{player: "A", hit: true, reaction: time.Second},
+					{player: "A", hit: false, reaction: 2 * time.Second},
+					{player: "A", hit: true, kills: 1, reaction: 3 * time.Second},
+				},
+			},
+			want: []Score{
+				{Player: "A", Hits: 2, Misses: 1, Kills: 1, Reaction: 2 * time.Second},
+			},
+		},
+		{
+			name: "multiple players, should rank by kills, hits and name",
+			args: args{
+				joined: []string{"A", "B", "C", "D"},
+				shots: []shot{
+					{player: "A", hit: true},
+					{player: "B", hit: true, kills: 1},
+					{player: "C", hit: true},
+				},
+			},
+			want: []Score{
+				{Player: "B", Hits: 1, Kills: 1},
+				{Player: "A", Hits: 1},
+				{Player: "C", Hits: 1},
+				{Player: "D"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScoreboard()
+			for _, p := range tt.args.joined {
+				s.Join(p)
+			}
+			for _, sh := range tt.args.shots {
+				s.Shot(sh.player, sh.hit, sh.kills, sh.reaction)
+			}
+			if got := s.Scores(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scoreboard.Scores() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package core
+
+import "time"
+
+// minWaveTick limits how fast the zombies can get in the later
+// waves, games which start out faster keep their own tick.
+const minWaveTick = time.Second
+
+// Wave describes a single wave of zombies in the waves game mode.
+type Wave struct {
+	Number    int
+	Zombies   int
+	HitPoints int
+	Tick      time.Duration
+}
+
+// NewWave returns the n-th wave of a game. Every wave brings
+// one more zombie and walks faster, every third wave
+// the zombies take one more hit to die.
+// The first wave matches the game options and
+// the later ones are never slower than it.
+func NewWave(n int, opts GameOptions) Wave {
+	zombies := opts.Zombies + n - 1
+	if zombies > maxZombies {
+		zombies = maxZombies
+	}
+
+	floor := minWaveTick
+	if opts.Tick < floor {
+		floor = opts.Tick
+	}
+	tick := opts.Tick
+	for i := 1; i < n && tick > floor; i++ {
+		tick = tick * 9 / 10
+	}
+	if tick < floor {
+		tick = floor
+	}
+
+	return Wave{
+		Number:    n,
+		Zombies:   zombies,
+		HitPoints: opts.HitPoints + (n-1)/3,
+		Tick:      tick,
+	}
+}
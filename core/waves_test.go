@@ -0,0 +1,79 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewWave(t *testing.T) {
+	type args struct {
+		n    int
+		opts GameOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want Wave
+	}{
+		{
+			name: "first wave, should match the options",
+			args: args{
+				n:    1,
+				opts: DefaultGameOptions(),
+			},
+			want: Wave{
+				Number:    1,
+				Zombies:   defaultZombies,
+				HitPoints: defaultHitPoints,
+				Tick:      defaultTick,
+			},
+		},
+		{
+			name: "fourth wave, should be bigger, tougher and faster",
+			args: args{
+				n:    4,
+				opts: DefaultGameOptions(),
+			},
+			want: Wave{
+				Number:    4,
+				Zombies:   defaultZombies + 3,
+				HitPoints: defaultHitPoints + 1,
+				Tick:      defaultTick * 729 / 1000,
+			},
+		},
+		{
+			name: "late wave, should be limited",
+			args: args{
+				n:    100,
+				opts: DefaultGameOptions(),
+			},
+			want: Wave{
+				Number:    100,
+				Zombies:   maxZombies,
+				HitPoints: defaultHitPoints + 33,
+				Tick:      time.Second,
+			},
+		},
+		{
+			name: "later wave of a fast game, should not slow down",
+			args: args{
+				n:    4,
+				opts: GameOptions{Zombies: 1, HitPoints: 1, Tick: 500 * time.Millisecond},
+			},
+			want: Wave{
+				Number:    4,
+				Zombies:   4,
+				HitPoints: 2,
+				Tick:      500 * time.Millisecond,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewWave(tt.args.n, tt.args.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewWave() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
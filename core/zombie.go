@@ -1,21 +1,75 @@
 package core
 
 import (
+	"fmt"
 	"math/rand"
 )
 
 type Zombie struct {
 	Name string
+	// Type is the name of the zombie type.
+	Type string
 	Hits int
-	x    int
-	y    int
+	// HitPoints is how many hits the zombie takes to die.
+	HitPoints int
+	x         int
+	y         int
+	// steps is how many times the zombie walks every tick.
+	steps int
+	// movement decides where the zombie walks,
+	// it walks at random if it's nil.
+	movement Movement
 }
 
+// defaultHitPoints is how many hits a zombie takes to die.
+const defaultHitPoints = 3
+
 var names = []string{"night-king", "snow-prince", "ice-face", "coldy-mcold"}
 
 // NewZombie returns a new zombie object
-func NewZombie() *Zombie {
+func NewZombie(name string, hitPoints int) *Zombie {
 	return &Zombie{
-		Name: names[rand.Intn(len(names))],
+		Name:      name,
+		HitPoints: hitPoints,
+	}
+}
+
+// zombieNames returns n unique names picked from the name pool
+// in random order, names are suffixed once the pool runs out.
+// Names which are already taken are skipped.
+func zombieNames(rnd *rand.Rand, names []string, n int, taken map[string]bool) []string {
+	pool := rnd.Perm(len(names))
+	picked := make([]string, 0, n)
+	for i := 0; len(picked) < n; i++ {
+		name := names[pool[i%len(names)]]
+		if round := i / len(names); round > 0 {
+			name = fmt.Sprintf("%s-%d", name, round+1)
+		}
+		if taken[name] {
+			continue
+		}
+		picked = append(picked, name)
+	}
+	return picked
+}
+
+// Position returns current x and y coordinates of the zombie.
+func (z *Zombie) Position() (int, int) {
+	return z.x, z.y
+}
+
+// Dead returns whether the zombie is dead.
+func (z *Zombie) Dead() bool {
+	return z.Hits >= z.HitPoints
+}
+
+// Info returns the state of the zombie as it's shown to the clients.
+func (z *Zombie) Info() ZombieInfo {
+	return ZombieInfo{
+		Name: z.Name,
+		Type: z.Type,
+		X:    z.x,
+		Y:    z.y,
+		Hits: z.Hits,
 	}
 }
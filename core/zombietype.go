@@ -0,0 +1,140 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ZombieType describes a kind of zombie, games
+// spawn zombies of the types they are given.
+type ZombieType struct {
+	Name string `json:"name"`
+	// HitPoints are added to the hit points of the game, so a type can be
+	// tougher or weaker than the rest. Every zombie takes at least a hit to die.
+	HitPoints int `json:"hitPoints"`
+	// Steps is how many times the zombie walks every tick.
+	Steps int `json:"steps"`
+	// Movement is how the zombie walks, unless the game
+	// picks a movement for every zombie. It walks at random by default.
+	Movement MovementKind `json:"movement,omitempty"`
+	// Names are the names the zombies of the type are given.
+	Names []string `json:"names"`
+}
+
+// Catalogue lists the zombie types a server knows,
+// games without types given spawn the first one.
+type Catalogue []ZombieType
+
+// maxSteps limits how fast the zombies are.
+var maxSteps = 5
+
+// DefaultCatalogue returns the catalogue used when the server isn't given one.
+func DefaultCatalogue() Catalogue {
+	return Catalogue{
+		{
+			Name:     "walker",
+			Steps:    1,
+			Movement: MovementRandom,
+			Names:    names,
+		},
+		{
+			Name:      "runner",
+			HitPoints: -1,
+			Steps:     2,
+			Movement:  MovementCharge,
+			Names:     []string{"frost-bite", "cold-snap", "blizzard"},
+		},
+		{
+			Name:      "brute",
+			HitPoints: 3,
+			Steps:     1,
+			Movement:  MovementWander,
+			Names:     []string{"glacier", "ice-wall", "avalanche"},
+		},
+		{
+			Name:      "wight",
+			HitPoints: 1,
+			Steps:     1,
+			Movement:  MovementDodge,
+			Names:     []string{"pale-shade", "frost-wraith", "white-walker"},
+		},
+	}
+}
+
+// ParseCatalogue parses a catalogue given as a JSON list of zombie types.
+func ParseCatalogue(data []byte) (Catalogue, error) {
+	var c Catalogue
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if len(c) == 0 {
+		return nil, errors.New("catalogue has no zombie types")
+	}
+
+	seen := make(map[string]bool, len(c))
+	for i, t := range c {
+		if !validName(t.Name) {
+			return nil, fmt.Errorf("zombie type %d should have a name without spaces", i+1)
+		}
+		if seen[t.Name] {
+			return nil, fmt.Errorf("zombie type %s is listed twice", t.Name)
+		}
+		seen[t.Name] = true
+
+		if t.HitPoints <= -maxHitPoints || t.HitPoints >= maxHitPoints {
+			return nil, fmt.Errorf("zombie type %s should have hit points between %d and %d", t.Name, -maxHitPoints+1, maxHitPoints-1)
+		}
+		if t.Steps < 1 || t.Steps > maxSteps {
+			return nil, fmt.Errorf("zombie type %s should have steps between 1 and %d", t.Name, maxSteps)
+		}
+		if t.Movement == "" {
+			c[i].Movement = MovementRandom
+		} else if _, err := ParseMovementKind(string(t.Movement)); err != nil {
+			return nil, fmt.Errorf("zombie type %s %w", t.Name, err)
+		}
+		if len(t.Names) == 0 {
+			return nil, fmt.Errorf("zombie type %s has no names", t.Name)
+		}
+		for _, name := range t.Names {
+			if !validName(name) {
+				return nil, fmt.Errorf("zombie type %s should have names without spaces", t.Name)
+			}
+		}
+	}
+	return c, nil
+}
+
+// Lookup returns the zombie types with the given names,
+// the first type of the catalogue if no names are given.
+func (c Catalogue) Lookup(names []string) ([]ZombieType, error) {
+	if len(names) == 0 {
+		return c[:1], nil
+	}
+
+	types := make([]ZombieType, 0, len(names))
+	for _, name := range names {
+		t, ok := c.get(name)
+		if !ok {
+			return nil, fmt.Errorf("zombie type %s doesn't exist", name)
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+func (c Catalogue) get(name string) (ZombieType, bool) {
+	for _, t := range c {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return ZombieType{}, false
+}
+
+// validName returns whether the name can be
+// sent as a single argument of a response.
+func validName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, " \t\r\n")
+}
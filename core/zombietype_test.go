@@ -0,0 +1,139 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseCatalogue(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Catalogue
+		wantErr bool
+	}{
+		{
+			name: "valid catalogue, should not error",
+			data: `[{"name":"walker","steps":1,"movement":"zigzag","names":["a","b"]},{"name":"brute","hitPoints":2,"steps":1,"names":["c"]}]`,
+			want: Catalogue{
+				{Name: "walker", Steps: 1, Movement: MovementZigZag, Names: []string{"a", "b"}},
+				{Name: "brute", HitPoints: 2, Steps: 1, Movement: MovementRandom, Names: []string{"c"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "not JSON, should error",
+			data:    `walker`,
+			wantErr: true,
+		},
+		{
+			name:    "no types, should error",
+			data:    `[]`,
+			wantErr: true,
+		},
+		{
+			name:    "type without a name, should error",
+			data:    `[{"steps":1,"names":["a"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "type listed twice, should error",
+			data:    `[{"name":"walker","steps":1,"names":["a"]},{"name":"walker","steps":1,"names":["b"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "type without steps, should error",
+			data:    `[{"name":"walker","names":["a"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "type with too many hit points, should error",
+			data:    `[{"name":"walker","hitPoints":100,"steps":1,"names":["a"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown movement, should error",
+			data:    `[{"name":"walker","steps":1,"movement":"mock","names":["a"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "type without names, should error",
+			data:    `[{"name":"walker","steps":1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "name with a space, should error",
+			data:    `[{"name":"walker","steps":1,"names":["night king"]}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCatalogue([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCatalogue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCatalogue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCatalogue_Default(t *testing.T) {
+	data, err := json.Marshal(DefaultCatalogue())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	got, err := ParseCatalogue(data)
+	if err != nil {
+		t.Fatalf("ParseCatalogue() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, DefaultCatalogue()) {
+		t.Errorf("ParseCatalogue() = %v, want %v", got, DefaultCatalogue())
+	}
+}
+
+func TestCatalogue_Lookup(t *testing.T) {
+	c := DefaultCatalogue()
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "no names, should return the first type",
+			names: nil,
+			want:  []string{"walker"},
+		},
+		{
+			name:  "known names, should return their types",
+			names: []string{"brute", "runner"},
+			want:  []string{"brute", "runner"},
+		},
+		{
+			name:    "unknown name, should error",
+			names:   []string{"walker", "mock"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			types, err := c.Lookup(tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Catalogue.Lookup() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var got []string
+			for _, t := range types {
+				got = append(got, t.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Catalogue.Lookup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
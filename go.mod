@@ -5,6 +5,8 @@ go 1.16
 require (
 	bitbucket.org/advbet/uid v0.0.0-20170404113236-581982c0f4f2
 	github.com/fln/pprotect v0.0.0-20160819093714-7d932ef9e7a2
+	github.com/gorilla/websocket v1.4.2
 	github.com/sirupsen/logrus v1.8.1
 	github.com/vrischmann/envconfig v1.3.0
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
 )
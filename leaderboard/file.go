@@ -0,0 +1,104 @@
+package leaderboard
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tomasmik/winter-is-coming/core"
+)
+
+// logName is the name of the results log in the data directory.
+const logName = "leaderboard.log"
+
+// File is a leaderboard which survives restarts. Every result is
+// appended to a log as a JSON line, the log is replayed when opened.
+type File struct {
+	mem *Memory
+	f   *os.File
+	m   sync.Mutex
+}
+
+// Open opens the leaderboard kept in the given directory,
+// the directory and the log are created if they don't exist.
+func Open(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, logName), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &File{
+		mem: NewMemory(),
+		f:   f,
+	}
+	if err := l.replay(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// replay reads the whole log in to memory. A torn last line,
+// left by a crash in the middle of a write, is cut off.
+func (l *File) replay() error {
+	r := bufio.NewReader(l.f)
+	var offset int64
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) == 0 {
+				return nil
+			}
+			return l.f.Truncate(offset)
+		}
+		if err != nil {
+			return err
+		}
+
+		var res core.Result
+		if err := json.Unmarshal(line, &res); err != nil {
+			return fmt.Errorf("leaderboard log line %d: %w", n, err)
+		}
+		l.mem.Record([]core.Result{res})
+		offset += int64(len(line))
+	}
+}
+
+// Record appends the results of a finished game to the log.
+func (l *File) Record(results []core.Result) error {
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	for _, r := range results {
+		if err := e.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+	if _, err := l.f.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err := l.f.Sync(); err != nil {
+		return err
+	}
+	return l.mem.Record(results)
+}
+
+// Top returns at most n best players.
+func (l *File) Top(n int) ([]core.Standing, error) {
+	return l.mem.Top(n)
+}
+
+// Close closes the log, the leaderboard can't be used afterwards.
+func (l *File) Close() error {
+	return l.f.Close()
+}
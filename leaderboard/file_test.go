@@ -0,0 +1,84 @@
+package leaderboard
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tomasmik/winter-is-coming/core"
+)
+
+func TestFile_Reopen(t *testing.T) {
+	tests := []struct {
+		name    string
+		results [][]core.Result
+		// tail is appended to the log before reopening it.
+		tail string
+		want []core.Standing
+	}{
+		{
+			name: "empty log, should be empty",
+			want: []core.Standing{},
+		},
+		{
+			name: "recorded games, should be kept",
+			results: [][]core.Result{
+				{{Player: "A", Won: true, Kills: 1, Hits: 3, Shots: 3}, {Player: "B"}},
+				{{Player: "B", Won: true, Kills: 2, Hits: 6, Shots: 8}},
+			},
+			want: []core.Standing{
+				{Player: "B", Wins: 1, Losses: 1, Kills: 2, Hits: 6, Shots: 8},
+				{Player: "A", Wins: 1, Kills: 1, Hits: 3, Shots: 3},
+			},
+		},
+		{
+			name: "torn last line, should be cut off",
+			results: [][]core.Result{
+				{{Player: "A", Won: true}},
+			},
+			tail: `{"player":"B","wo`,
+			want: []core.Standing{
+				{Player: "A", Wins: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l, err := Open(dir)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			for _, res := range tt.results {
+				if err := l.Record(res); err != nil {
+					t.Fatalf("File.Record() error = %v", err)
+				}
+			}
+			l.Close()
+
+			if tt.tail != "" {
+				f, err := os.OpenFile(filepath.Join(dir, logName), os.O_WRONLY|os.O_APPEND, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				f.WriteString(tt.tail)
+				f.Close()
+			}
+
+			l, err = Open(dir)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer l.Close()
+
+			got, err := l.Top(core.DefaultLeaderboardSize)
+			if err != nil {
+				t.Fatalf("File.Top() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("File.Top() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package leaderboard
+
+import (
+	"sync"
+
+	"github.com/tomasmik/winter-is-coming/core"
+)
+
+// Memory is a leaderboard which is kept only
+// in memory, it's lost once the server stops.
+type Memory struct {
+	standings map[string]*core.Standing
+	m         sync.RWMutex
+}
+
+// NewMemory returns an empty in memory leaderboard.
+func NewMemory() *Memory {
+	return &Memory{
+		standings: make(map[string]*core.Standing),
+	}
+}
+
+// Record counts the results of a finished game.
+func (l *Memory) Record(results []core.Result) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	for _, r := range results {
+		s, ok := l.standings[r.Player]
+		if !ok {
+			s = &core.Standing{Player: r.Player}
+			l.standings[r.Player] = s
+		}
+		s.Add(r)
+	}
+	return nil
+}
+
+// Top returns at most n best players.
+func (l *Memory) Top(n int) ([]core.Standing, error) {
+	l.m.RLock()
+	standings := make([]core.Standing, 0, len(l.standings))
+	for _, s := range l.standings {
+		standings = append(standings, *s)
+	}
+	l.m.RUnlock()
+
+	core.RankStandings(standings)
+	if len(standings) > n {
+		standings = standings[:n]
+	}
+	return standings, nil
+}
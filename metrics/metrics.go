@@ -0,0 +1,112 @@
+// Package metrics keeps server metrics and writes
+// them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds of histogram
+// buckets, in seconds, fit for measuring latency.
+var DefaultBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+// CounterVec is a set of counters told apart by a single label.
+// It's safe for concurrent use.
+type CounterVec struct {
+	label  string
+	values map[string]uint64
+	m      sync.Mutex
+}
+
+// NewCounterVec returns an empty set of counters with the given label.
+func NewCounterVec(label string) *CounterVec {
+	return &CounterVec{
+		label:  label,
+		values: make(map[string]uint64),
+	}
+}
+
+// Inc increases the counter of the label value by one.
+func (c *CounterVec) Inc(value string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.values[value]++
+}
+
+// Value returns the counter of the label value.
+func (c *CounterVec) Value(value string) uint64 {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.values[value]
+}
+
+// snapshot returns the label values sorted with their counters.
+func (c *CounterVec) snapshot() ([]string, []uint64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	values := make([]string, 0, len(c.values))
+	for v := range c.values {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+
+	counts := make([]uint64, len(values))
+	for i, v := range values {
+		counts[i] = c.values[v]
+	}
+	return values, counts
+}
+
+// Histogram counts observations in buckets.
+// It's safe for concurrent use.
+type Histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+	m       sync.Mutex
+}
+
+// NewHistogram returns an empty histogram with
+// the given sorted bucket upper bounds.
+func NewHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+// Observe adds a single observation to the histogram.
+func (h *Histogram) Observe(v float64) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.sum += v
+	h.count++
+	// Buckets are kept cumulative as they are written.
+	for i, le := range h.buckets {
+		if v <= le {
+			h.counts[i]++
+		}
+	}
+}
+
+// Count returns how many observations were made.
+func (h *Histogram) Count() uint64 {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.count
+}
+
+// snapshot returns the cumulative bucket counts,
+// the sum and the count of the observations.
+func (h *Histogram) snapshot() ([]uint64, float64, uint64) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	counts := make([]uint64, len(h.counts))
+	copy(counts, h.counts)
+	return counts, h.sum, h.count
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Writer writes metrics in the text exposition format.
+// The first write error is kept and every later write is skipped.
+type Writer struct {
+	w   io.Writer
+	err error
+}
+
+// NewWriter returns a writer writing to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Counter writes a single counter.
+func (w *Writer) Counter(name, help string, v float64) {
+	w.header(name, help, "counter")
+	w.sample(name, "", v)
+}
+
+// Gauge writes a single gauge.
+func (w *Writer) Gauge(name, help string, v float64) {
+	w.header(name, help, "gauge")
+	w.sample(name, "", v)
+}
+
+// CounterVec writes a counter for every label value of the set.
+func (w *Writer) CounterVec(name, help string, c *CounterVec) {
+	w.header(name, help, "counter")
+	values, counts := c.snapshot()
+	for i, v := range values {
+		w.sample(name, label(c.label, v), float64(counts[i]))
+	}
+}
+
+// Histogram writes the buckets, the sum and the count of a histogram.
+func (w *Writer) Histogram(name, help string, h *Histogram) {
+	w.header(name, help, "histogram")
+	counts, sum, count := h.snapshot()
+	for i, le := range h.buckets {
+		w.sample(name+"_bucket", label("le", formatFloat(le)), float64(counts[i]))
+	}
+	w.sample(name+"_bucket", label("le", "+Inf"), float64(count))
+	w.sample(name+"_sum", "", sum)
+	w.sample(name+"_count", "", float64(count))
+}
+
+// Err returns the first error the writer has run into.
+func (w *Writer) Err() error {
+	return w.err
+}
+
+func (w *Writer) header(name, help, typ string) {
+	w.printf("# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, typ)
+}
+
+func (w *Writer) sample(name, labels string, v float64) {
+	w.printf("%s%s %s\n", name, labels, formatFloat(v))
+}
+
+func (w *Writer) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.w, format, args...)
+}
+
+func label(name, value string) string {
+	return fmt.Sprintf(`{%s="%s"}`, name, escapeLabel(value))
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
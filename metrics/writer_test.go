@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *Writer)
+		want  string
+	}{
+		{
+			name: "counter, should be written with its type",
+			write: func(w *Writer) {
+				w.Counter("shots_total", "Shots fired.", 3)
+			},
+			want: "# HELP shots_total Shots fired.\n" +
+				"# TYPE shots_total counter\n" +
+				"shots_total 3\n",
+		},
+		{
+			name: "gauge, should be written with its type",
+			write: func(w *Writer) {
+				w.Gauge("games", "Active games.\nNow.", 0.5)
+			},
+			want: "# HELP games Active games.\\nNow.\n" +
+				"# TYPE games gauge\n" +
+				"games 0.5\n",
+		},
+		{
+			name: "counter vec, should be sorted by label value",
+			write: func(w *Writer) {
+				c := NewCounterVec("command")
+				c.Inc("SHOOT")
+				c.Inc("JOINGAME")
+				c.Inc("SHOOT")
+				c.Inc(`bad"one`)
+				w.CounterVec("commands_total", "Commands.", c)
+			},
+			want: "# HELP commands_total Commands.\n" +
+				"# TYPE commands_total counter\n" +
+				"commands_total{command=\"JOINGAME\"} 1\n" +
+				"commands_total{command=\"SHOOT\"} 2\n" +
+				"commands_total{command=\"bad\\\"one\"} 1\n",
+		},
+		{
+			name: "histogram, should have cumulative buckets",
+			write: func(w *Writer) {
+				h := NewHistogram([]float64{0.1, 1})
+				h.Observe(0.0625)
+				h.Observe(0.5)
+				h.Observe(2)
+				w.Histogram("latency_seconds", "Latency.", h)
+			},
+			want: "# HELP latency_seconds Latency.\n" +
+				"# TYPE latency_seconds histogram\n" +
+				"latency_seconds_bucket{le=\"0.1\"} 1\n" +
+				"latency_seconds_bucket{le=\"1\"} 2\n" +
+				"latency_seconds_bucket{le=\"+Inf\"} 3\n" +
+				"latency_seconds_sum 2.5625\n" +
+				"latency_seconds_count 3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(&buf)
+			tt.write(w)
+			if err := w.Err(); err != nil {
+				t.Fatalf("Err() = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+type failWriter struct{ n int }
+
+func (f *failWriter) Write(p []byte) (int, error) {
+	f.n++
+	return 0, errors.New("closed")
+}
+
+func TestWriter_Err(t *testing.T) {
+	f := &failWriter{}
+	w := NewWriter(f)
+	w.Counter("a", "A.", 1)
+	w.Gauge("b", "B.", 1)
+	if w.Err() == nil {
+		t.Error("Err() = nil, want error")
+	}
+	if f.n != 1 {
+		t.Errorf("writes = %d, want 1", f.n)
+	}
+}
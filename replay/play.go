@@ -0,0 +1,83 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tomasmik/winter-is-coming/core"
+)
+
+// Player plays recorded events back keeping the time between them.
+type Player struct {
+	// Speed speeds the playback up, 0 plays
+	// everything back without waiting.
+	Speed float64
+	// Public plays back only the plain responses the whole game
+	// got, just like a spectator would see them. Otherwise every
+	// event is annotated with its time and audience.
+	Public bool
+	// Sleep waits between events, time.Sleep is used if it's nil.
+	Sleep func(time.Duration)
+}
+
+// Play writes the events to w.
+func (p Player) Play(w io.Writer, events []Event) error {
+	sleep := p.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	var last time.Time
+	for i, e := range events {
+		if i > 0 && p.Speed > 0 {
+			sleep(time.Duration(float64(e.At.Sub(last)) / p.Speed))
+		}
+		last = e.At
+
+		line, ok := p.format(e, events[0].At)
+		if !ok {
+			continue
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// format returns the line of an event,
+// false is returned if it isn't played back.
+func (p Player) format(e Event, start time.Time) (string, bool) {
+	if p.Public {
+		if e.Response == nil || e.Response.To != "" {
+			return "", false
+		}
+		// Only the winner of a competitive game is left out,
+		// spectators are told who has won instead.
+		if e.Response.Over && e.Response.Except != "" {
+			line, err := core.EncodeResponse(core.EncodingText, core.ProtocolVersion, core.NewResponseFinishWinner(e.Response.Except))
+			return line, err == nil
+		}
+		return e.Response.Line, true
+	}
+
+	at := fmt.Sprintf("+%.3fs", e.At.Sub(start).Seconds())
+	switch {
+	case e.Shot != nil:
+		return fmt.Sprintf("%s %s SHOOT %d %d", at, e.Shot.Player, e.Shot.X, e.Shot.Y), true
+	case e.Response != nil:
+		// Multi-line responses are indented, so
+		// every event starts with its time.
+		line := strings.ReplaceAll(e.Response.Line, "\n", "\n\t")
+		switch {
+		case e.Response.To != "":
+			return fmt.Sprintf("%s [%s] %s", at, e.Response.To, line), true
+		case e.Response.Except != "":
+			return fmt.Sprintf("%s [-%s] %s", at, e.Response.Except, line), true
+		}
+		return fmt.Sprintf("%s %s", at, line), true
+	}
+	return "", false
+}
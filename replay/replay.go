@@ -0,0 +1,124 @@
+// Package replay records what happens in a game,
+// so it can be played back after the game is over.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Ext is the extension of replay files.
+const Ext = ".replay"
+
+// Event is a single recorded event of a game, it's
+// either a shot of a player or a response of the game.
+type Event struct {
+	At       time.Time `json:"at"`
+	Shot     *Shot     `json:"shot,omitempty"`
+	Response *Response `json:"response,omitempty"`
+}
+
+// Shot is a shot a player has made.
+type Shot struct {
+	Player string `json:"player"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+}
+
+// Response is a response of the game in the plain-text encoding.
+type Response struct {
+	// To is set when the response was sent only to a single player,
+	// Except when it was sent to everyone but a single player.
+	To     string `json:"to,omitempty"`
+	Except string `json:"except,omitempty"`
+	Over   bool   `json:"over,omitempty"`
+	Line   string `json:"line"`
+}
+
+// Recorder writes the events of a single game to a replay file,
+// every event is written as a JSON line and flushed right away,
+// so a crash loses at most the line being written. Recorder
+// isn't safe for concurrent use.
+type Recorder struct {
+	f   *os.File
+	w   *bufio.Writer
+	e   *json.Encoder
+	err error
+}
+
+// Create creates a replay file for a game started at the given time,
+// the directory is created if it doesn't exist.
+func Create(dir, game string, started time.Time) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, FileName(game, started)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	w := bufio.NewWriter(f)
+	return &Recorder{
+		f: f,
+		w: w,
+		e: json.NewEncoder(w),
+	}, nil
+}
+
+// FileName returns the name of the replay file of a game. Game names
+// are escaped, so they can't point outside of the replay directory.
+func FileName(game string, started time.Time) string {
+	return fmt.Sprintf("%s-%s%s", url.PathEscape(game), started.UTC().Format("20060102T150405.000000000"), Ext)
+}
+
+// Record writes a single event. Once writing fails
+// the rest of the events are dropped, the error is
+// returned when the recorder is closed.
+func (r *Recorder) Record(e Event) {
+	if r.err != nil {
+		return
+	}
+	if r.err = r.e.Encode(e); r.err == nil {
+		r.err = r.w.Flush()
+	}
+}
+
+// Close writes the buffered events and closes the file.
+func (r *Recorder) Close() error {
+	err := r.err
+	if ferr := r.w.Flush(); err == nil {
+		err = ferr
+	}
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Read reads the recorded events. A torn last line,
+// left by a crash in the middle of a write, is skipped.
+func Read(r io.Reader) ([]Event, error) {
+	br := bufio.NewReader(r)
+	var events []Event
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("replay line %d: %w", n, err)
+		}
+		events = append(events, e)
+	}
+}
@@ -0,0 +1,162 @@
+package replay
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2021, 11, 20, 12, 0, 0, 0, time.UTC)
+
+var events = []Event{
+	{At: start, Response: &Response{Line: "WALK night-king 0 1"}},
+	{At: start.Add(500 * time.Millisecond), Shot: &Shot{Player: "alice", X: 0, Y: 1}},
+	{At: start.Add(510 * time.Millisecond), Response: &Response{Line: "BOOM alice 1 night-king"}},
+	{At: start.Add(2 * time.Second), Response: &Response{Line: "SCORE 1\nPLAYER alice 1 0 1 500"}},
+	{At: start.Add(2 * time.Second), Response: &Response{To: "alice", Line: "FINISH WINNER alice"}},
+	{At: start.Add(2 * time.Second), Response: &Response{Except: "alice", Over: true, Line: "FINISH LOST"}},
+}
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	r, err := Create(dir, "../g", start)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, e := range events {
+		r.Record(e)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	path := filepath.Join(dir, FileName("../g", start))
+	if filepath.Dir(path) != dir {
+		t.Fatalf("replay %s is outside of %s", path, dir)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("opening the replay: %v", err)
+	}
+	defer f.Close()
+
+	got, err := Read(f)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, events) {
+		t.Errorf("Read() = %v, want %v", got, events)
+	}
+}
+
+func TestRecorderFlushesEveryEvent(t *testing.T) {
+	dir := t.TempDir()
+	r, err := Create(dir, "g", start)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	defer r.Close()
+	r.Record(events[0])
+
+	f, err := os.Open(filepath.Join(dir, FileName("g", start)))
+	if err != nil {
+		t.Fatalf("opening the replay: %v", err)
+	}
+	defer f.Close()
+
+	got, err := Read(f)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, events[:1]) {
+		t.Errorf("Read() = %v, want %v", got, events[:1])
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "empty replay, should have no events",
+			in:   "",
+			want: 0,
+		},
+		{
+			name: "torn last line, should be skipped",
+			in:   `{"at":"2021-11-20T12:00:00Z","shot":{"player":"a","x":1,"y":2}}` + "\n" + `{"at":"2021-11-20T12:0`,
+			want: 1,
+		},
+		{
+			name:    "corrupted line, should error",
+			in:      "nonsense\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(strings.NewReader(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Read() = %d events, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestPlayer_Play(t *testing.T) {
+	tests := []struct {
+		name   string
+		player Player
+		want   string
+		slept  []time.Duration
+	}{
+		{
+			name:   "annotated at double speed, should show every event",
+			player: Player{Speed: 2},
+			want: "+0.000s WALK night-king 0 1\n" +
+				"+0.500s alice SHOOT 0 1\n" +
+				"+0.510s BOOM alice 1 night-king\n" +
+				"+2.000s SCORE 1\n\tPLAYER alice 1 0 1 500\n" +
+				"+2.000s [alice] FINISH WINNER alice\n" +
+				"+2.000s [-alice] FINISH LOST\n",
+			slept: []time.Duration{250 * time.Millisecond, 5 * time.Millisecond, 745 * time.Millisecond, 0, 0},
+		},
+		{
+			name:   "public without waiting, should show only what everyone got",
+			player: Player{Public: true},
+			want: "WALK night-king 0 1\n" +
+				"BOOM alice 1 night-king\n" +
+				"SCORE 1\nPLAYER alice 1 0 1 500\n" +
+				"FINISH WINNER alice\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var slept []time.Duration
+			tt.player.Sleep = func(d time.Duration) {
+				slept = append(slept, d)
+			}
+
+			var buf bytes.Buffer
+			if err := tt.player.Play(&buf, events); err != nil {
+				t.Fatalf("Play() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Play() wrote:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(slept, tt.slept) {
+				t.Errorf("Play() slept %v, want %v", slept, tt.slept)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/tomasmik/winter-is-coming/core"
+)
+
+// PlayerInfo describes a single player as it's shown to the admins.
+type PlayerInfo struct {
+	Name     string  `json:"name"`
+	Game     string  `json:"game"`
+	Hits     int     `json:"hits"`
+	Shots    int     `json:"shots"`
+	Accuracy float64 `json:"accuracy"`
+	// Dropped is set when the connection of the
+	// player has dropped and he can still resume.
+	Dropped bool `json:"dropped"`
+}
+
+// do runs fn in the event loop of the keeper and waits for it.
+func (g *GameKeeper) do(fn func()) error {
+	done := make(chan struct{})
+	select {
+	case g.amsg <- func() {
+		defer close(done)
+		fn()
+	}:
+	case <-g.done:
+		return errStopped
+	}
+	<-done
+	return nil
+}
+
+// Games returns every active game sorted by name.
+func (g *GameKeeper) Games() ([]core.GameInfo, error) {
+	var games []core.GameInfo
+	err := g.do(func() {
+		games = g.games()
+	})
+	return games, err
+}
+
+// Game returns a single active game.
+func (g *GameKeeper) Game(name string) (core.GameInfo, error) {
+	var info core.GameInfo
+	var found bool
+	if err := g.do(func() {
+		gin, ok := g.instances[name]
+		if ok {
+			info, found = g.gameInfo(gin), true
+		}
+	}); err != nil {
+		return info, err
+	}
+	if !found {
+		return info, errNoGame
+	}
+	return info, nil
+}
+
+// Players returns every player of the server sorted by name.
+func (g *GameKeeper) Players() ([]PlayerInfo, error) {
+	var players []PlayerInfo
+	err := g.do(func() {
+		players = make([]PlayerInfo, 0, len(g.players))
+		for sign, p := range g.players {
+			_, dropped := g.dropped[sign]
+			players = append(players, PlayerInfo{
+				Name:     p.Name,
+				Game:     p.GameName,
+				Hits:     p.Hits,
+				Shots:    p.Shots,
+				Accuracy: p.Accuracy(),
+				Dropped:  dropped,
+			})
+		}
+	})
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+	return players, err
+}
+
+// EndGame stops a game without finishing it,
+// the players in it are told the game has ended.
+func (g *GameKeeper) EndGame(name string) error {
+	var err error
+	if derr := g.do(func() {
+		gin, ok := g.instances[name]
+		if !ok {
+			err = errNoGame
+			return
+		}
+		gin.stop()
+		delete(g.instances, name)
+		g.stopRecording(gin)
+
+		for sign, p := range g.players {
+			if p.GameName != name {
+				continue
+			}
+			p.GameName = ""
+			g.players[sign] = p
+			g.send(sign, core.NewResponseError(errGameEnded))
+		}
+		g.endSpectating(name)
+		g.log.WithField("game", name).Info("game ended by an admin")
+	}); derr != nil {
+		return derr
+	}
+	return err
+}
+
+// SpawnZombie adds a zombie to an active game.
+func (g *GameKeeper) SpawnZombie(name string) (core.ZombieInfo, error) {
+	var gin *gameInstance
+	if err := g.do(func() {
+		gin = g.instances[name]
+	}); err != nil {
+		return core.ZombieInfo{}, err
+	}
+	if gin == nil {
+		return core.ZombieInfo{}, errNoGame
+	}
+	// Instance is waited on outside of the event loop, as it
+	// might be waiting for the keeper to take its responses.
+	return gin.spawn()
+}
+
+// Admin returns a handler serving the HTTP/JSON admin
+// API and the metrics at /metrics.
+func (s *Server) Admin() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/games", s.adminGames)
+	mux.HandleFunc("/games/", s.adminGame)
+	mux.HandleFunc("/players", s.adminPlayers)
+	mux.Handle("/metrics", s.Metrics())
+	return mux
+}
+
+// GET /games
+func (s *Server) adminGames(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errMethod)
+		return
+	}
+	games, err := s.gp.Games()
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	for i := range games {
+		normalizeGameInfo(&games[i])
+	}
+	writeJSON(w, http.StatusOK, games)
+}
+
+// GET /games/{name}, DELETE /games/{name}
+// and POST /games/{name}/zombies
+func (s *Server) adminGame(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/games/"), "/")
+	name := parts[0]
+	if name == "" || len(parts) > 2 || (len(parts) == 2 && parts[1] != "zombies") {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		z, err := s.gp.SpawnZombie(name)
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, z)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		info, err := s.gp.Game(name)
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		normalizeGameInfo(&info)
+		writeJSON(w, http.StatusOK, info)
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		if err := s.gp.EndGame(name); err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, errMethod)
+	}
+}
+
+// GET /players
+func (s *Server) adminPlayers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, errMethod)
+		return
+	}
+	players, err := s.gp.Players()
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, players)
+}
+
+var (
+	errMethod   = errors.New("method not allowed")
+	errNotFound = errors.New("not found")
+)
+
+// statusOf returns the HTTP status of a keeper error.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, errNoGame):
+		return http.StatusNotFound
+	case errors.Is(err, core.ErrTooManyZombies):
+		return http.StatusConflict
+	case errors.Is(err, errStopped):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// normalizeGameInfo makes empty lists show up as [] instead of null.
+func normalizeGameInfo(info *core.GameInfo) {
+	if info.Players == nil {
+		info.Players = []string{}
+	}
+	if info.Zombies == nil {
+		info.Zombies = []core.ZombieInfo{}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
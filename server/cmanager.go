@@ -11,7 +11,11 @@ type Cmanager struct {
 	// indexes at which clients are placed, so removing
 	// them is much easier.
 	clients map[net.Conn]struct{}
-	m       sync.Mutex
+	// closed is set by UnregAll, no clients
+	// can be registered afterwards.
+	closed bool
+	wg     sync.WaitGroup
+	m      sync.Mutex
 }
 
 func NewCmanager() *Cmanager {
@@ -20,10 +24,20 @@ func NewCmanager() *Cmanager {
 	}
 }
 
-func (m *Cmanager) Reg(c net.Conn) {
+// Reg registers a client, it returns false and closes the
+// client if the manager is closed. Done must be called once
+// a registered client has been handled.
+func (m *Cmanager) Reg(c net.Conn) bool {
 	m.m.Lock()
 	defer m.m.Unlock()
+
+	if m.closed {
+		c.Close()
+		return false
+	}
 	m.clients[c] = struct{}{}
+	m.wg.Add(1)
+	return true
 }
 
 func (m *Cmanager) Unreg(c net.Conn) {
@@ -38,8 +52,26 @@ func (m *Cmanager) UnregAll() {
 	m.m.Lock()
 	defer m.m.Unlock()
 
+	m.closed = true
 	for c := range m.clients {
 		c.Close()
 		delete(m.clients, c)
 	}
 }
+
+// Done marks a registered client as handled.
+func (m *Cmanager) Done() {
+	m.wg.Done()
+}
+
+// Wait blocks until every registered client has been handled.
+func (m *Cmanager) Wait() {
+	m.wg.Wait()
+}
+
+// Count returns how many clients are connected.
+func (m *Cmanager) Count() int {
+	m.m.Lock()
+	defer m.m.Unlock()
+	return len(m.clients)
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// readTimeout is how long a connection can stay idle, it's
+	// extended by every write as well, so spectators which only
+	// read are kept connected for as long as they are streamed to.
+	readTimeout  = 60 * time.Second
+	writeTimeout = 5 * time.Second
+)
+
+// lineConn is a client connection which carries commands
+// and responses as lines, no matter the transport.
+type lineConn interface {
+	// ReadLine returns the next line without the line ending,
+	// io.EOF is returned once the client closes the connection.
+	ReadLine() (string, error)
+	// WriteLine writes a single line, adding the line ending if needed.
+	WriteLine(line string) error
+}
+
+// tcpConn carries newline delimited lines over raw TCP.
+type tcpConn struct {
+	c    net.Conn
+	r    *bufio.Reader
+	idle time.Duration
+}
+
+func newTCPConn(c net.Conn) *tcpConn {
+	return &tcpConn{
+		c:    c,
+		r:    bufio.NewReader(c),
+		idle: readTimeout,
+	}
+}
+
+func (t *tcpConn) ReadLine() (string, error) {
+	t.c.SetReadDeadline(time.Now().Add(t.idle))
+	return t.r.ReadString('\n')
+}
+
+func (t *tcpConn) WriteLine(line string) error {
+	t.c.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := t.c.Write([]byte(line + "\n")); err != nil {
+		return err
+	}
+	return t.c.SetReadDeadline(time.Now().Add(t.idle))
+}
+
+// wsConn carries a single line in every WebSocket text frame.
+type wsConn struct {
+	c *websocket.Conn
+}
+
+func (w *wsConn) ReadLine() (string, error) {
+	for {
+		w.c.SetReadDeadline(time.Now().Add(readTimeout))
+		typ, data, err := w.c.ReadMessage()
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			return "", io.EOF
+		}
+		if err != nil {
+			return "", err
+		}
+		// Binary frames aren't a part of the protocol.
+		if typ != websocket.TextMessage {
+			continue
+		}
+		return string(data), nil
+	}
+}
+
+func (w *wsConn) WriteLine(line string) error {
+	w.c.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := w.c.WriteMessage(websocket.TextMessage, []byte(line)); err != nil {
+		return err
+	}
+	return w.c.SetReadDeadline(time.Now().Add(readTimeout))
+}
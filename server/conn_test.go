@@ -0,0 +1,48 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTCPConn_WritesKeepReading(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+	c := &tcpConn{c: server, r: bufio.NewReader(server), idle: 50 * time.Millisecond}
+
+	read := make(chan error, 1)
+	go func() {
+		_, err := c.ReadLine()
+		read <- err
+	}()
+
+	// The client only reads, like a spectator does, for
+	// a lot longer than the connection can stay idle.
+	r := bufio.NewReader(client)
+	for i := 0; i < 10; i++ {
+		time.Sleep(20 * time.Millisecond)
+		go c.WriteLine("WALK night-king 1 2 walker")
+		if _, err := r.ReadString('\n'); err != nil {
+			t.Fatalf("reading a line: %v", err)
+		}
+	}
+
+	select {
+	case err := <-read:
+		t.Fatalf("ReadLine() error = %v, want it to wait", err)
+	default:
+	}
+
+	// Nothing is written anymore, so the connection times out.
+	select {
+	case err := <-read:
+		if err == nil {
+			t.Error("ReadLine() error = nil, want a timeout")
+		}
+	case <-time.After(time.Second):
+		t.Error("ReadLine() never timed out")
+	}
+}
@@ -2,41 +2,116 @@ package server
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/tomasmik/winter-is-coming/clock"
 	"github.com/tomasmik/winter-is-coming/core"
 )
 
-// gameInstance is a game instance with a player and a zombie
+// gameInstance is a game instance with players and zombies
 type gameInstance struct {
-	name string
-	gb   *core.Gameboard
+	name  string
+	gb    *core.Gameboard
+	opts  core.GameOptions
+	clock clock.Clock
 
-	shotCh chan shot
-	respCh chan instanceResp
+	shotCh  chan shot
+	joinCh  chan join
+	spawnCh chan spawn
+	respCh  chan instanceResp
+	// out queues the responses before they are passed to
+	// the keeper, see forward.
+	out chan instanceResp
+	// queued is shared with the keeper, which
+	// exposes it as the response backlog.
+	queued *int64
 	// done channel is shared with the keeper.
 	// When keeper shuts down, all instances should exit.
 	done chan struct{}
-	o    sync.Once
+	// quit is closed by the keeper when the instance
+	// should exit without finishing the game.
+	quit chan struct{}
+	// stopped is closed when the instance exits, so
+	// the keeper never blocks on a finished instance.
+	stopped chan struct{}
+	o       sync.Once
+
+	// wave is the current wave in the waves game mode.
+	wave int
+	// scores track how every player in the game is doing,
+	// walked is used to measure the players reaction time.
+	scores *core.Scoreboard
+	walked time.Time
+
+	// players, idleSince and recording are owned by the keeper,
+	// the first two are used to stop instances nobody is playing in.
+	players   int
+	idleSince time.Time
+	// recording is set while the game is being recorded.
+	recording bool
+
+	// state is a snapshot of the board, it is updated by the
+	// instance so the keeper can read it without blocking.
+	state gameState
+	sm    sync.RWMutex
+}
+
+type gameState struct {
+	zombies []core.ZombieInfo
 }
 
 type shot struct {
 	name string
 	x    int
 	y    int
+	req  core.Request
+}
+
+type join struct {
+	name    string
+	players []string
+	req     core.Request
+}
+
+// spawn is a request to add a zombie to the board,
+// the new zombie or an error is sent back.
+type spawn struct {
+	zombie chan core.ZombieInfo
+	err    chan error
 }
 
 type instanceResp struct {
 	IsOver   bool
 	GameName string
-	Resp     core.Response
+	// From is the instance which created the response,
+	// a game might be recreated under the same name.
+	From *gameInstance
+	// To is set when the response is meant only
+	// for a single player of the game.
+	To string
+	// Except is set when the response is meant
+	// for everyone in the game but a single player.
+	Except string
+	// Shooter is set when the response is
+	// a result of the players shot.
+	Shooter string
+	Hit     bool
+	// ReplyTo is set when the response is a reply to the
+	// request of a player, he's sent it tagged with Req.
+	ReplyTo string
+	Req     core.Request
+	Resp    core.Response
+	// Results are set only when the game is over,
+	// they are recorded in the leaderboard.
+	Results []core.Result
 }
 
 // Run starts a game instance thread.
 // Game instance doesn't know if there are any players
 // playing in it, it will run until:
-// - A player joins a game instance and wins.
-// - Zombie reaches the wall.
+// - A player joins a game instance and wins (not in waves mode).
+// - Any zombie reaches the wall.
 // - Application is shutdown.
 // Note:
 // I got confused regarding this part in the task description
@@ -44,54 +119,325 @@ type instanceResp struct {
 // if only one player is in the game and is shooting the zombie
 // does it also count at 1 shot wins or do we then allow for more shots
 // and change it back to the 2+ player rule when someone joins?
-// So this is now a team game against the zombies,
-// though I guess it's clearly obviuos that converting to 1 shot wins
-// isn't a hard thing to do.
+// So by default this is a team game against the zombies, the
+// "first to shoot wins" rule is the competitive game mode, where
+// nobody can shoot until there are 2+ players in the game.
 func (g *gameInstance) Run() {
-	ticker := time.NewTicker(time.Second * 4)
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		g.forward()
+	}()
+	// The queued responses are passed on only after stopped is
+	// closed, so the keeper can't block on a finished instance.
+	defer func() {
+		close(g.out)
+		<-forwarded
+	}()
+	defer close(g.stopped)
+
+	ticker := g.clock.NewTicker(g.opts.Tick)
 	defer ticker.Stop()
 
 	// Walk once at the start.
 	g.o.Do(func() {
-		x, y := g.gb.ZombieWalk()
-		g.newMsg(false, core.NewResponseWalk(g.gb.Zombie.Name, x, y))
+		if g.opts.Mode == core.GameModeWaves {
+			g.wave = 1
+			g.newMsg(false, core.NewResponseWave(g.wave))
+		}
+		g.walk()
 	})
 
 	for {
 		select {
 		case <-g.done:
 			return
+		case <-g.quit:
+			return
+		case j := <-g.joinCh:
+			g.scores.Join(j.name)
+			g.newReplyTo(j.name, j.req, core.NewResponseJoined(g.name, j.players, g.gb.ZombiesInfo()))
+		case sp := <-g.spawnCh:
+			z, err := g.gb.SpawnZombie(g.hitPoints())
+			if err != nil {
+				sp.err <- err
+				break
+			}
+			g.updateState()
+			x, y := z.Position()
+			g.newMsg(false, core.NewResponseWalk(z.Name, z.Type, x, y))
+			sp.zombie <- z.Info()
 		case shot := <-g.shotCh:
-			if g.gb.HitZombie(shot.x, shot.y) {
-				g.newMsg(false, core.NewResponseBoom(shot.name, g.gb.Zombie.Name, g.gb.Zombie.Hits))
+			hit := g.gb.HitZombies(shot.x, shot.y)
+			g.updateState()
+			g.scores.Shot(shot.name, len(hit) > 0, kills(hit), g.clock.Since(g.walked))
+			if len(hit) == 0 {
+				g.newShotMsg(shot, false, core.NewResponseMiss(shot.name, shot.x, shot.y))
+			}
+			for i, z := range hit {
+				boom := core.NewResponseBoom(shot.name, z.Name, z.Type, z.Hits)
+				// A shot is counted once, even if it hits multiple zombies.
+				if i == 0 {
+					g.newShotMsg(shot, true, boom)
+					continue
+				}
+				g.newMsg(false, boom)
+			}
+			if g.opts.Mode == core.GameModeCompetitive && kills(hit) > 0 {
+				g.newScoreMsg()
+				g.newMsgTo(shot.name, core.NewResponseFinishWinner(shot.name))
+				g.newFinishMsg(core.NewResponseFinish(false), false, shot.name)
+				return
 			}
-			if g.gb.ZombieDead() {
-				g.newMsg(true, core.NewResponseFinish(true))
+			if g.gb.ZombiesDead() && g.opts.Mode == core.GameModeWaves {
+				g.nextWave(ticker)
+			} else if g.gb.ZombiesDead() {
+				g.newScoreMsg()
+				g.newFinishMsg(core.NewResponseFinish(true), true, "")
 				return
 			}
-		case <-ticker.C:
-			x, y := g.gb.ZombieWalk()
-			g.newMsg(false, core.NewResponseWalk(g.gb.Zombie.Name, x, y))
+		case <-ticker.C():
+			g.walk()
 			if g.gb.ZombieReachedWall() {
-				g.newMsg(true, core.NewResponseFinish(false))
+				g.newScoreMsg()
+				g.newFinishMsg(g.lost(), false, "")
 				return
 			}
 		}
 	}
 }
 
+// forward passes the responses of the instance to the keeper.
+// Responses are queued, so the instance never blocks on the keeper
+// while the keeper might be blocked on sending to the instance.
+func (g *gameInstance) forward() {
+	var queue []instanceResp
+	in := g.out
+	for in != nil || len(queue) > 0 {
+		var (
+			send chan instanceResp
+			next instanceResp
+		)
+		if len(queue) > 0 {
+			send, next = g.respCh, queue[0]
+		}
+
+		select {
+		case <-g.done:
+			// Nobody reads the queue anymore, but the instance
+			// might still be sending, so it's drained until closed.
+			atomic.AddInt64(g.queued, -int64(len(queue)))
+			if in != nil {
+				for range in {
+				}
+			}
+			return
+		case resp, ok := <-in:
+			if !ok {
+				in = nil
+				break
+			}
+			queue = append(queue, resp)
+			atomic.AddInt64(g.queued, 1)
+		case send <- next:
+			queue = queue[1:]
+			atomic.AddInt64(g.queued, -1)
+		}
+	}
+}
+
+// kills returns how many of the hit zombies died.
+func kills(hit []*core.Zombie) int {
+	n := 0
+	for _, z := range hit {
+		if z.Dead() {
+			n++
+		}
+	}
+	return n
+}
+
+// nextWave spawns the next wave of zombies,
+// which are more numerous, tougher and faster.
+func (g *gameInstance) nextWave(ticker clock.Ticker) {
+	g.wave++
+	w := core.NewWave(g.wave, g.opts)
+	g.gb.Spawn(w.Zombies, w.HitPoints)
+	g.updateState()
+	ticker.Reset(w.Tick)
+	g.newMsg(false, core.NewResponseWave(w.Number))
+}
+
+// hitPoints returns how many hits the zombies of the game
+// currently take to die, it only changes in the waves mode.
+func (g *gameInstance) hitPoints() int {
+	wave := g.wave
+	if wave < 1 {
+		wave = 1
+	}
+	return core.NewWave(wave, g.opts).HitPoints
+}
+
+// lost returns the response sent when zombies win the game.
+func (g *gameInstance) lost() core.Response {
+	if g.opts.Mode == core.GameModeWaves {
+		return core.NewResponseFinishWaves(g.wave - 1)
+	}
+	return core.NewResponseFinish(false)
+}
+
+// walk makes every zombie walk, letting the players know.
+func (g *gameInstance) walk() {
+	g.gb.ZombiesWalk()
+	g.walked = g.clock.Now()
+	g.updateState()
+	for _, z := range g.gb.Zombies {
+		x, y := z.Position()
+		g.newMsg(false, core.NewResponseWalk(z.Name, z.Type, x, y))
+	}
+}
+
 func (g *gameInstance) newMsg(isOver bool, resp core.Response) {
-	g.respCh <- instanceResp{
+	g.out <- instanceResp{
 		IsOver:   isOver,
 		Resp:     resp,
 		GameName: g.name,
+		From:     g,
 	}
 }
 
-func (g *gameInstance) shoot(name string, x, y int) {
-	g.shotCh <- shot{
-		name: name,
-		x:    x,
-		y:    y,
+// newFinishMsg ends the game with the results of every player.
+// In the competitive mode the winner gets his own response, so
+// he is the only one who wins and the only one left out.
+func (g *gameInstance) newFinishMsg(resp core.Response, won bool, winner string) {
+	scores := g.scores.Scores()
+	results := make([]core.Result, 0, len(scores))
+	for _, sc := range scores {
+		results = append(results, core.Result{
+			Player: sc.Player,
+			Won:    won || sc.Player == winner,
+			Kills:  sc.Kills,
+			Hits:   sc.Hits,
+			Shots:  sc.Hits + sc.Misses,
+		})
 	}
+
+	g.out <- instanceResp{
+		IsOver:   true,
+		Except:   winner,
+		Resp:     resp,
+		Results:  results,
+		GameName: g.name,
+		From:     g,
+	}
+}
+
+// newScoreMsg lets the players know how everyone did,
+// it's sent right before the game finishes.
+func (g *gameInstance) newScoreMsg() {
+	g.newMsg(false, core.NewResponseScore(g.scores.Scores()))
+}
+
+// updateState updates the board snapshot, it must
+// be called after every change of the board.
+func (g *gameInstance) updateState() {
+	zombies := g.gb.ZombiesInfo()
+
+	g.sm.Lock()
+	defer g.sm.Unlock()
+	g.state = gameState{
+		zombies: zombies,
+	}
+}
+
+// snapshot returns the latest snapshot of the board.
+func (g *gameInstance) snapshot() gameState {
+	g.sm.RLock()
+	defer g.sm.RUnlock()
+	return g.state
+}
+
+// newMsgTo creates a message which is sent only to the given player.
+func (g *gameInstance) newMsgTo(name string, resp core.Response) {
+	g.out <- instanceResp{
+		To:       name,
+		Resp:     resp,
+		GameName: g.name,
+		From:     g,
+	}
+}
+
+// newReplyTo creates a message which is sent only to
+// the given player as a reply to his request.
+func (g *gameInstance) newReplyTo(name string, req core.Request, resp core.Response) {
+	g.out <- instanceResp{
+		To:       name,
+		ReplyTo:  name,
+		Req:      req,
+		Resp:     resp,
+		GameName: g.name,
+		From:     g,
+	}
+}
+
+// newShotMsg creates a message with the result of a players shot.
+// Depending on game options a miss is sent only to the shooter.
+func (g *gameInstance) newShotMsg(s shot, hit bool, resp core.Response) {
+	to := ""
+	if !hit && g.opts.Misses == core.MissModeShooter {
+		to = s.name
+	}
+	g.out <- instanceResp{
+		To:       to,
+		Shooter:  s.name,
+		Hit:      hit,
+		ReplyTo:  s.name,
+		Req:      s.req,
+		Resp:     resp,
+		GameName: g.name,
+		From:     g,
+	}
+}
+
+func (g *gameInstance) shoot(name string, x, y int, req core.Request) {
+	select {
+	case g.shotCh <- shot{name: name, x: x, y: y, req: req}:
+	case <-g.stopped:
+	}
+}
+
+// spawn adds a zombie to the board. It waits for
+// the instance, so it must not be called by the keeper.
+func (g *gameInstance) spawn() (core.ZombieInfo, error) {
+	sp := spawn{
+		zombie: make(chan core.ZombieInfo, 1),
+		err:    make(chan error, 1),
+	}
+	select {
+	case g.spawnCh <- sp:
+	case <-g.stopped:
+		return core.ZombieInfo{}, errNoGame
+	}
+
+	select {
+	case z := <-sp.zombie:
+		return z, nil
+	case err := <-sp.err:
+		return core.ZombieInfo{}, err
+	}
+}
+
+// join lets the instance know that a player has joined,
+// so it can send the player a snapshot of the board.
+func (g *gameInstance) join(name string, players []string, req core.Request) {
+	select {
+	case g.joinCh <- join{name: name, players: players, req: req}:
+	case <-g.stopped:
+	}
+}
+
+// stop makes the instance exit without finishing the game.
+// It must be called only once.
+func (g *gameInstance) stop() {
+	close(g.quit)
 }
@@ -1,44 +1,198 @@
 package server
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"bitbucket.org/advbet/uid"
 	"github.com/sirupsen/logrus"
+	"github.com/tomasmik/winter-is-coming/clock"
 	"github.com/tomasmik/winter-is-coming/core"
+	"github.com/tomasmik/winter-is-coming/leaderboard"
+	"github.com/tomasmik/winter-is-coming/replay"
 )
 
 // GameKeeper is used to manage game instances.
 // It tracks players and instances that they are in.
 type GameKeeper struct {
 	players   map[uid.UUID]core.Player
-	instances map[string]gameInstance
+	instances map[string]*gameInstance
+	// dropped are the players whose connection has
+	// dropped, they are kept until the grace window ends.
+	dropped map[uid.UUID]*droppedSession
+	// spectators are the connections watching a game,
+	// they don't have a player session.
+	spectators map[uid.UUID]spectator
 
 	umsg chan core.Message
 	gmsg chan instanceResp
+	// amsg carries the admin requests, they
+	// are run in the event loop to avoid races.
+	amsg chan func()
+
+	// reapAfter is how long a game without
+	// players is kept running before it's stopped.
+	reapAfter time.Duration
+	// resumeGrace is how long a dropped player
+	// can resume his session, 0 disables resuming.
+	resumeGrace time.Duration
+	stats       keeperStats
+	board       core.Leaderboard
+	// clock is shared with the instances.
+	clock clock.Clock
+	// defaults are the options of new games, the
+	// options players give are applied on top.
+	defaults core.GameOptions
+	// catalogue lists the zombie types games can spawn.
+	catalogue core.Catalogue
+	// replayDir is where the games are recorded,
+	// recording is disabled if it's empty.
+	replayDir string
+	// queued counts the responses the instances have queued,
+	// it's shared with them, so it must be accessed atomically.
+	queued int64
+	// results queues the results of finished games, they are
+	// recorded by recordResults so the disk doesn't block the loop.
+	results chan gameResults
+	// replays queues the replay events of the games, they are
+	// written by recordReplays so the disk doesn't block the loop.
+	replays chan replayOp
 
 	done chan struct{}
 	log  *logrus.Entry
 	iwg  sync.WaitGroup
 }
 
+// keeperStats are counters which describe
+// what the keeper has done over its lifetime.
+type keeperStats struct {
+	reaped int
+	shots  int
+	hits   int
+	won    int
+	lost   int
+}
+
+// spectator is a connection watching a single game.
+type spectator struct {
+	game string
+	resp chan core.Response
+}
+
+// droppedSession keeps the responses a dropped
+// player misses, so they can be sent once he resumes.
+type droppedSession struct {
+	since  time.Time
+	missed []core.Response
+}
+
+// backlog is a batch of responses sent to a connection as a single
+// value, the connection writer writes them one by one. It's used so
+// the keeper doesn't block on every response a resuming player missed.
+type backlog []core.Response
+
+func (b backlog) String() string {
+	lines := make([]string, 0, len(b))
+	for _, resp := range b {
+		lines = append(lines, resp.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (b backlog) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]core.Response(b))
+}
+
+// gameResults are the results of a single finished game.
+type gameResults struct {
+	game    string
+	results []core.Result
+}
+
+// replayOp is a single change of the replay of a game. Replay is
+// created on start and closed on stop, otherwise the event is
+// recorded with the response encoded as its line.
+type replayOp struct {
+	game  *gameInstance
+	start bool
+	stop  bool
+	event replay.Event
+	resp  core.Response
+}
+
+// maxMissed limits how many responses are kept for a
+// dropped player, the oldest ones are thrown away first.
+const maxMissed = 1000
+
+// maxQueuedResults limits how many finished games can wait to be
+// recorded, the keeper blocks when the leaderboard falls this far behind.
+const maxQueuedResults = 64
+
+// maxQueuedReplays limits how many replay events can wait to be
+// written, the keeper blocks when the disk falls this far behind.
+const maxQueuedReplays = 1024
+
 var (
 	errNoSession   = errors.New("haven't created a session")
 	errHaveSession = errors.New("already created a session")
 	errNotInGame   = errors.New("not in a game")
 	errNameTaken   = errors.New("name taken")
+	errInGame      = errors.New("already in this game")
+	errGameExists  = errors.New("game already exists, options can't be changed")
+	errNoGame      = errors.New("no such game")
+	errLeaderboard = errors.New("leaderboard is unavailable")
+	errNoResume    = errors.New("no session to resume")
+	errGameEnded   = errors.New("game was ended by the server")
+	errStopped     = errors.New("server is stopping")
+	errSpectator   = errors.New("spectators can't shoot")
+	errIsPlayer    = errors.New("players can't spectate")
+
+	errNotEnoughPlayers = fmt.Errorf("competitive game needs at least %d players", core.CompetitivePlayers)
 )
 
-// NewGameKeeper returns a GameKeeper object.
-func NewGameKeeper() *GameKeeper {
+// NewGameKeeper returns a GameKeeper object
+// which manages its games as configured.
+func NewGameKeeper(conf Config) *GameKeeper {
+	if conf.Leaderboard == nil {
+		conf.Leaderboard = leaderboard.NewMemory()
+	}
+	if conf.Clock == nil {
+		conf.Clock = clock.Real()
+	}
+	defaults := core.DefaultGameOptions()
+	if conf.Defaults != nil {
+		defaults = *conf.Defaults
+	}
+	if len(conf.Catalogue) == 0 {
+		conf.Catalogue = core.DefaultCatalogue()
+	}
 	return &GameKeeper{
-		players:   make(map[uid.UUID]core.Player),
-		instances: make(map[string]gameInstance),
-		gmsg:      make(chan instanceResp, 16),
-		umsg:      make(chan core.Message, 16),
-		log:       logrus.WithField("thread", "game-keeper"),
-		done:      make(chan struct{}),
+		players:     make(map[uid.UUID]core.Player),
+		instances:   make(map[string]*gameInstance),
+		dropped:     make(map[uid.UUID]*droppedSession),
+		spectators:  make(map[uid.UUID]spectator),
+		reapAfter:   conf.ReapAfter,
+		resumeGrace: conf.ResumeGrace,
+		board:       conf.Leaderboard,
+		replayDir:   conf.ReplayDir,
+		clock:       conf.Clock,
+		defaults:    defaults,
+		catalogue:   conf.Catalogue,
+		gmsg:        make(chan instanceResp, 16),
+		amsg:        make(chan func()),
+		umsg:        make(chan core.Message, 16),
+		results:     make(chan gameResults, maxQueuedResults),
+		replays:     make(chan replayOp, maxQueuedReplays),
+		log:         logrus.WithField("thread", "game-keeper"),
+		done:        make(chan struct{}),
 	}
 }
 
@@ -56,20 +210,59 @@ func (g *GameKeeper) Run() {
 	g.log.Info("started")
 	defer g.log.Info("stopped")
 
-	cleanup := func(sign uid.UUID) {
-		_, ok := g.players[sign]
-		if !ok {
-			return
-		}
-		delete(g.players, sign)
-	}
+	reap := g.clock.NewTicker(time.Second)
+	defer reap.Stop()
+	stats := g.clock.NewTicker(time.Minute)
+	defer stats.Stop()
+
+	recorded := make(chan struct{})
+	go func() {
+		defer close(recorded)
+		g.recordResults()
+	}()
+	replayed := make(chan struct{})
+	go func() {
+		defer close(replayed)
+		g.recordReplays()
+	}()
 
 	for {
 		select {
 		case <-g.done:
 			g.iwg.Wait()
+			for _, gin := range g.instances {
+				g.stopRecording(gin)
+			}
+			close(g.results)
+			close(g.replays)
+			<-recorded
+			<-replayed
 			return
+		case <-reap.C():
+			g.reapIdle()
+			g.expireDropped()
+		case <-stats.C():
+			g.logStats()
+		case fn := <-g.amsg:
+			fn()
 		case msg := <-g.gmsg:
+			// Instance might have been stopped and
+			// a new one created under the same name.
+			if g.instances[msg.GameName] != msg.From {
+				break
+			}
+			g.recordResponse(msg)
+			if msg.Shooter != "" {
+				g.stats.shots++
+				if msg.Hit {
+					g.stats.hits++
+				}
+			}
+			// Results are filtered before the players are
+			// moved out of the game, see stayedResults.
+			if msg.IsOver {
+				msg.Results = g.stayedResults(msg.GameName, msg.Results)
+			}
 			// TODO: This is not really efficient and I am
 			// aware of this, but this is the easiest way
 			// and it works OK while we dont have a million users.
@@ -77,19 +270,40 @@ func (g *GameKeeper) Run() {
 				if p.GameName != msg.GameName {
 					continue
 				}
+				if msg.Shooter == p.Name {
+					p.Shots++
+					if msg.Hit {
+						p.Hits++
+					}
+					g.players[sign] = p
+				}
+				if msg.To != "" && msg.To != p.Name {
+					continue
+				}
 
-				p.Resp <- msg.Resp
+				resp := msg.Resp
+				if msg.ReplyTo == p.Name {
+					resp = msg.Req.Reply(resp)
+				}
+				if msg.Except != p.Name {
+					g.send(sign, resp)
+				}
 				if msg.IsOver {
 					p.GameName = ""
 					g.players[sign] = p
 				}
 			}
+			g.spectate(msg)
 			if msg.IsOver {
 				delete(g.instances, msg.GameName)
+				g.stopRecording(msg.From)
+				g.record(msg.GameName, msg.Results)
+				g.countFinished(msg.Results)
 			}
 		case msg := <-g.umsg:
 			if msg.DC {
-				cleanup(msg.Signature)
+				g.disconnect(msg.Signature)
+				msg.Close()
 				break
 			}
 
@@ -101,8 +315,20 @@ func (g *GameKeeper) Run() {
 			switch typ {
 			case core.CommandTypeJoinServer:
 				g.msgJoinServer(&msg)
+			case core.CommandTypeResume:
+				g.msgResume(&msg)
 			case core.CommandTypeJoinGame:
 				g.msgJoinGame(&msg)
+			case core.CommandTypeLeaveGame:
+				g.msgLeaveGame(&msg)
+			case core.CommandTypeListGames:
+				g.msgListGames(&msg)
+			case core.CommandTypeGameInfo:
+				g.msgGameInfo(&msg)
+			case core.CommandTypeLeaderboard:
+				g.msgLeaderboard(&msg)
+			case core.CommandTypeSpectate:
+				g.msgSpectate(&msg)
 			case core.CommandTypeShoot:
 				g.msgShoot(&msg)
 			}
@@ -110,14 +336,30 @@ func (g *GameKeeper) Run() {
 	}
 }
 
-func (g *GameKeeper) newGameInstance(name string) *gameInstance {
-	return &gameInstance{
-		name:   name,
-		gb:     core.NewGameBoard(),
-		shotCh: make(chan shot),
-		respCh: g.gmsg,
-		done:   g.done,
+func (g *GameKeeper) newGameInstance(name string, opts core.GameOptions, types []core.ZombieType) *gameInstance {
+	gb := core.NewGameBoard(opts, types)
+	// The seed is kept even if it was picked by the board,
+	// so the game can be created again.
+	opts.Seed = gb.Seed
+	gin := &gameInstance{
+		name:    name,
+		gb:      gb,
+		opts:    opts,
+		clock:   g.clock,
+		scores:  core.NewScoreboard(),
+		shotCh:  make(chan shot),
+		spawnCh: make(chan spawn),
+		joinCh:  make(chan join),
+		respCh:  g.gmsg,
+		out:     make(chan instanceResp),
+		queued:  &g.queued,
+		done:    g.done,
+		quit:    make(chan struct{}),
+		stopped: make(chan struct{}),
 	}
+	g.startRecording(gin)
+	gin.updateState()
+	return gin
 }
 
 func (g *GameKeeper) msgJoinServer(msg *core.Message) {
@@ -142,7 +384,110 @@ func (g *GameKeeper) msgJoinServer(msg *core.Message) {
 	}
 
 	player := core.NewPlayer(cmd.Name, msg.Signature, msg.Resp)
+	if g.resumeGrace > 0 {
+		token, err := newToken()
+		if err != nil {
+			g.log.WithError(err).Error("creating a resume token")
+		}
+		player.Token = token
+	}
 	g.players[msg.Signature] = *player
+	// Joining the server as a player ends spectating.
+	delete(g.spectators, msg.Signature)
+	msg.Respond(core.NewResponseWelcome(player.Name, player.Token))
+}
+
+// newToken returns a random session resume token.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// msgResume moves the session of a dropped player to
+// the new connection, sending him what he has missed.
+func (g *GameKeeper) msgResume(msg *core.Message) {
+	cmd, err := core.ParseCommandResume(msg.Message)
+	if err != nil {
+		msg.RespondErr(err)
+		return
+	}
+
+	if _, ok := g.players[msg.Signature]; ok {
+		msg.RespondErr(errHaveSession)
+		return
+	}
+
+	var sign uid.UUID
+	var d *droppedSession
+	for s, ds := range g.dropped {
+		token := g.players[s].Token
+		if subtle.ConstantTimeCompare([]byte(token), []byte(cmd.Token)) == 1 {
+			sign, d = s, ds
+			break
+		}
+	}
+	if d == nil {
+		msg.RespondErr(errNoResume)
+		return
+	}
+
+	p := g.players[sign]
+	p.Resp = msg.Resp
+	delete(g.dropped, sign)
+	delete(g.players, sign)
+	g.players[msg.Signature] = p
+	delete(g.spectators, msg.Signature)
+
+	resumed := msg.Request.Reply(core.NewResponseResumed(p.Name, len(d.missed)))
+	msg.Resp <- append(backlog{resumed}, d.missed...)
+}
+
+// disconnect handles a dropped connection. The player is
+// kept for the grace window, so he can resume the session.
+func (g *GameKeeper) disconnect(sign uid.UUID) {
+	delete(g.spectators, sign)
+	if _, ok := g.players[sign]; !ok {
+		return
+	}
+	if g.resumeGrace == 0 {
+		g.removePlayer(sign)
+		return
+	}
+	g.dropped[sign] = &droppedSession{since: g.clock.Now()}
+}
+
+// expireDropped removes dropped players
+// who haven't resumed in the grace window.
+func (g *GameKeeper) expireDropped() {
+	for sign, d := range g.dropped {
+		if g.clock.Since(d.since) < g.resumeGrace {
+			continue
+		}
+		g.removePlayer(sign)
+	}
+}
+
+// removePlayer ends the players session.
+func (g *GameKeeper) removePlayer(sign uid.UUID) {
+	g.leaveGame(sign)
+	delete(g.players, sign)
+	delete(g.dropped, sign)
+}
+
+// send sends a response to the player, responses
+// for dropped players are kept until they resume.
+func (g *GameKeeper) send(sign uid.UUID, resp core.Response) {
+	if d, ok := g.dropped[sign]; ok {
+		if len(d.missed) == maxMissed {
+			d.missed = d.missed[1:]
+		}
+		d.missed = append(d.missed, resp)
+		return
+	}
+	g.players[sign].Resp <- resp
 }
 
 func (g *GameKeeper) msgJoinGame(msg *core.Message) {
@@ -158,25 +503,359 @@ func (g *GameKeeper) msgJoinGame(msg *core.Message) {
 		return
 	}
 
+	gin, ok := g.instances[cmd.GameName]
+	if ok && len(cmd.Options) > 0 {
+		msg.RespondErr(errGameExists)
+		return
+	}
+
+	opts, err := g.defaults.Apply(cmd.Options)
+	if err != nil {
+		msg.RespondErr(err)
+		return
+	}
+	var types []core.ZombieType
+	if !ok {
+		types, err = g.catalogue.Lookup(opts.Types)
+		if err != nil {
+			msg.RespondErr(err)
+			return
+		}
+	}
+
+	if p.GameName == cmd.GameName {
+		msg.RespondErr(errInGame)
+		return
+	}
+	g.leaveGame(msg.Signature)
+
 	p.GameName = cmd.GameName
 	g.players[msg.Signature] = p
 
 	// If a game instance with this name already exists
 	// dont start a new thread.
-	if _, ok := g.instances[cmd.GameName]; ok {
+	if !ok {
+		gin = g.newGameInstance(cmd.GameName, opts, types)
+		g.instances[gin.name] = gin
+
+		g.iwg.Add(1)
+		go func() {
+			defer g.iwg.Done()
+			gin.Run()
+		}()
+	}
+	gin.players++
+	gin.join(p.Name, g.gamePlayers(gin.name), msg.Request)
+}
+
+func (g *GameKeeper) msgLeaveGame(msg *core.Message) {
+	if _, err := core.ParseCommandLeaveGame(msg.Message); err != nil {
+		msg.RespondErr(err)
 		return
 	}
-	gin := g.newGameInstance(cmd.GameName)
-	g.instances[gin.name] = *gin
 
-	g.iwg.Add(1)
-	go func() {
-		defer g.iwg.Done()
-		gin.Run()
-	}()
+	p, ok := g.players[msg.Signature]
+	if !ok {
+		msg.RespondErr(errNoSession)
+		return
+	}
+
+	if p.GameName == "" {
+		msg.RespondErr(errNotInGame)
+		return
+	}
+
+	g.leaveGame(msg.Signature)
+	msg.Respond(core.NewResponseLeft(p.Name))
+}
+
+// leaveGame removes the player from his game letting the remaining
+// players know. When nobody is left the game instance becomes idle.
+func (g *GameKeeper) leaveGame(sign uid.UUID) {
+	p := g.players[sign]
+	name := p.GameName
+	if name == "" {
+		return
+	}
+	p.GameName = ""
+	g.players[sign] = p
+
+	for other, op := range g.players {
+		if op.GameName != name {
+			continue
+		}
+		g.send(other, core.NewResponseLeft(p.Name))
+	}
+
+	if gin, ok := g.instances[name]; ok {
+		gin.players--
+		if gin.players == 0 {
+			gin.idleSince = g.clock.Now()
+		}
+	}
+}
+
+// reapIdle stops game instances which
+// were left without players for too long.
+func (g *GameKeeper) reapIdle() {
+	for name, gin := range g.instances {
+		if gin.players > 0 || g.clock.Since(gin.idleSince) < g.reapAfter {
+			continue
+		}
+
+		gin.stop()
+		delete(g.instances, name)
+		g.stopRecording(gin)
+		g.endSpectating(name)
+		g.stats.reaped++
+		g.log.WithField("game", name).Info("reaped idle game")
+	}
+}
+
+func (g *GameKeeper) logStats() {
+	g.log.WithFields(logrus.Fields{
+		"players": len(g.players),
+		"dropped": len(g.dropped),
+		"games":   len(g.instances),
+		"reaped":  g.stats.reaped,
+	}).Info("stats")
+}
+
+func (g *GameKeeper) msgListGames(msg *core.Message) {
+	if _, err := core.ParseCommandListGames(msg.Message); err != nil {
+		msg.RespondErr(err)
+		return
+	}
+
+	msg.Respond(core.NewResponseGames(g.games()))
+}
+
+// games returns every active game sorted by name.
+func (g *GameKeeper) games() []core.GameInfo {
+	names := make([]string, 0, len(g.instances))
+	for name := range g.instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	games := make([]core.GameInfo, 0, len(names))
+	for _, name := range names {
+		games = append(games, g.gameInfo(g.instances[name]))
+	}
+	return games
+}
+
+func (g *GameKeeper) msgGameInfo(msg *core.Message) {
+	cmd, err := core.ParseCommandGameInfo(msg.Message)
+	if err != nil {
+		msg.RespondErr(err)
+		return
+	}
+
+	gin, ok := g.instances[cmd.GameName]
+	if !ok {
+		msg.RespondErr(errNoGame)
+		return
+	}
+	msg.Respond(core.NewResponseGameInfo(g.gameInfo(gin)))
+}
+
+// gameInfo returns the state of a game as it's shown to the clients.
+func (g *GameKeeper) gameInfo(gin *gameInstance) core.GameInfo {
+	state := gin.snapshot()
+	return core.GameInfo{
+		Name:    gin.name,
+		Mode:    gin.opts.Mode,
+		Width:   gin.opts.Width,
+		Height:  gin.opts.Height,
+		Seed:    gin.opts.Seed,
+		Players: g.gamePlayers(gin.name),
+		Zombies: state.zombies,
+	}
+}
+
+// gamePlayers returns sorted names of the players in a game.
+func (g *GameKeeper) gamePlayers(name string) []string {
+	var names []string
+	for _, p := range g.players {
+		if p.GameName == name {
+			names = append(names, p.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stayedResults keeps the results of the players who are still in
+// the game when it's over. The instance doesn't know who has left,
+// so a player can't leave and still be credited with the win.
+func (g *GameKeeper) stayedResults(name string, results []core.Result) []core.Result {
+	stayed := make(map[string]bool)
+	for _, p := range g.players {
+		if p.GameName == name {
+			stayed[p.Name] = true
+		}
+	}
+
+	var kept []core.Result
+	for _, r := range results {
+		if stayed[r.Player] {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// countFinished counts a finished game as won
+// if any of the players has won it.
+func (g *GameKeeper) countFinished(results []core.Result) {
+	for _, r := range results {
+		if r.Won {
+			g.stats.won++
+			return
+		}
+	}
+	g.stats.lost++
+}
+
+// startRecording queues the creation of the replay
+// of a new game, if recording is enabled.
+func (g *GameKeeper) startRecording(gin *gameInstance) {
+	if g.replayDir == "" {
+		return
+	}
+	gin.recording = true
+	g.replays <- replayOp{
+		game:  gin,
+		start: true,
+		event: replay.Event{At: g.clock.Now()},
+	}
+}
+
+// recordResponse queues a response of a game to be recorded in its replay.
+func (g *GameKeeper) recordResponse(msg instanceResp) {
+	if !msg.From.recording {
+		return
+	}
+	g.replays <- replayOp{
+		game: msg.From,
+		event: replay.Event{
+			At: g.clock.Now(),
+			Response: &replay.Response{
+				To:     msg.To,
+				Except: msg.Except,
+				Over:   msg.IsOver,
+			},
+		},
+		resp: msg.Resp,
+	}
+}
+
+// recordShot queues a shot passed to a game to be recorded in its replay.
+func (g *GameKeeper) recordShot(gin *gameInstance, name string, x, y int) {
+	if !gin.recording {
+		return
+	}
+	g.replays <- replayOp{
+		game: gin,
+		event: replay.Event{
+			At:   g.clock.Now(),
+			Shot: &replay.Shot{Player: name, X: x, Y: y},
+		},
+	}
+}
+
+// stopRecording queues the replay of a game which is over to be closed.
+func (g *GameKeeper) stopRecording(gin *gameInstance) {
+	if !gin.recording {
+		return
+	}
+	gin.recording = false
+	g.replays <- replayOp{game: gin, stop: true}
+}
+
+// recordReplays writes the queued replay events until the queue
+// is closed, the replays are written to a disk so it's done
+// off the event loop. Recorders are owned by it alone.
+func (g *GameKeeper) recordReplays() {
+	recs := make(map[*gameInstance]*replay.Recorder)
+	for op := range g.replays {
+		// Name of an instance never changes, so it's safe to read.
+		log := g.log.WithField("game", op.game.name)
+		if op.start {
+			rec, err := replay.Create(g.replayDir, op.game.name, op.event.At)
+			if err != nil {
+				log.WithError(err).Error("creating a replay")
+				continue
+			}
+			recs[op.game] = rec
+			continue
+		}
+
+		rec, ok := recs[op.game]
+		if !ok {
+			continue
+		}
+		if op.stop {
+			delete(recs, op.game)
+			if err := rec.Close(); err != nil {
+				log.WithError(err).Error("recording a replay")
+			}
+			continue
+		}
+		if op.resp != nil {
+			line, err := core.EncodeResponse(core.EncodingText, core.ProtocolVersion, op.resp)
+			if err != nil {
+				log.WithError(err).Error("encoding a replay response")
+				continue
+			}
+			op.event.Response.Line = line
+		}
+		rec.Record(op.event)
+	}
+}
+
+// record queues the results of a finished game to be saved in the leaderboard.
+func (g *GameKeeper) record(name string, results []core.Result) {
+	if len(results) == 0 {
+		return
+	}
+	g.results <- gameResults{game: name, results: results}
+}
+
+// recordResults saves the queued results in the leaderboard
+// until the queue is closed, the leaderboard might be
+// writing to a disk so it's done off the event loop.
+func (g *GameKeeper) recordResults() {
+	for r := range g.results {
+		if err := g.board.Record(r.results); err != nil {
+			g.log.WithError(err).WithField("game", r.game).Error("recording game results")
+		}
+	}
+}
+
+func (g *GameKeeper) msgLeaderboard(msg *core.Message) {
+	cmd, err := core.ParseCommandLeaderboard(msg.Message)
+	if err != nil {
+		msg.RespondErr(err)
+		return
+	}
+
+	standings, err := g.board.Top(cmd.N)
+	if err != nil {
+		g.log.WithError(err).Error("reading leaderboard")
+		msg.RespondErr(errLeaderboard)
+		return
+	}
+	msg.Respond(core.NewResponseLeaderboard(standings))
 }
 
 func (g *GameKeeper) msgShoot(msg *core.Message) {
+	if _, ok := g.spectators[msg.Signature]; ok {
+		msg.RespondErr(errSpectator)
+		return
+	}
+
 	p, ok := g.players[msg.Signature]
 	if !ok {
 		msg.RespondErr(errNoSession)
@@ -194,7 +873,76 @@ func (g *GameKeeper) msgShoot(msg *core.Message) {
 		return
 	}
 	gin := g.instances[p.GameName]
-	gin.shoot(p.Name, cmd.X, cmd.Y)
+	if gin.opts.Mode == core.GameModeCompetitive && gin.players < core.CompetitivePlayers {
+		msg.RespondErr(errNotEnoughPlayers)
+		return
+	}
+	// Board size doesn't change, so it's safe
+	// to check it while the instance is running.
+	if err := gin.gb.ValidateShot(cmd.X, cmd.Y); err != nil {
+		msg.RespondErr(err)
+		return
+	}
+	g.recordShot(gin, p.Name, cmd.X, cmd.Y)
+	gin.shoot(p.Name, cmd.X, cmd.Y, msg.Request)
+}
+
+// msgSpectate subscribes the connection to a game. It's sent the
+// state of the game and then everything that happens in it.
+func (g *GameKeeper) msgSpectate(msg *core.Message) {
+	cmd, err := core.ParseCommandSpectate(msg.Message)
+	if err != nil {
+		msg.RespondErr(err)
+		return
+	}
+
+	if _, ok := g.players[msg.Signature]; ok {
+		msg.RespondErr(errIsPlayer)
+		return
+	}
+
+	gin, ok := g.instances[cmd.GameName]
+	if !ok {
+		msg.RespondErr(errNoGame)
+		return
+	}
+	g.spectators[msg.Signature] = spectator{game: gin.name, resp: msg.Resp}
+	msg.Respond(core.NewResponseGameInfo(g.gameInfo(gin)))
+}
+
+// spectate passes a response meant for the whole game to its
+// spectators. In the competitive mode the spectators are told
+// who has won instead of the FINISH the losers get.
+func (g *GameKeeper) spectate(msg instanceResp) {
+	if msg.To != "" {
+		return
+	}
+	resp := msg.Resp
+	if msg.IsOver && msg.Except != "" {
+		resp = core.NewResponseFinishWinner(msg.Except)
+	}
+
+	for sign, s := range g.spectators {
+		if s.game != msg.GameName {
+			continue
+		}
+		s.resp <- resp
+		if msg.IsOver {
+			delete(g.spectators, sign)
+		}
+	}
+}
+
+// endSpectating lets the spectators of a game
+// which was stopped without finishing know about it.
+func (g *GameKeeper) endSpectating(name string) {
+	for sign, s := range g.spectators {
+		if s.game != name {
+			continue
+		}
+		s.resp <- core.NewResponseError(errGameEnded)
+		delete(g.spectators, sign)
+	}
 }
 
 // Stop will stop the game streamer
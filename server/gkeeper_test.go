@@ -0,0 +1,432 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"bitbucket.org/advbet/uid"
+	"github.com/tomasmik/winter-is-coming/clock"
+	"github.com/tomasmik/winter-is-coming/core"
+	"github.com/tomasmik/winter-is-coming/replay"
+)
+
+// keeperTickers counts the tickers the keeper runs itself.
+const keeperTickers = 2
+
+// zombieHitPoints is how many hits a zombie takes to die in a classic game.
+const zombieHitPoints = 3
+
+// startKeeper runs a keeper driven by a fake clock, it's stopped once the test ends.
+func startKeeper(t *testing.T) (*GameKeeper, *clock.Fake) {
+	t.Helper()
+	return startKeeperWith(t, Config{ReapAfter: time.Minute})
+}
+
+// startKeeperWith runs a keeper configured as given, but driven by a fake clock.
+func startKeeperWith(t *testing.T, conf Config) (*GameKeeper, *clock.Fake) {
+	t.Helper()
+	fake := clock.NewFake(time.Date(2021, 11, 20, 12, 0, 0, 0, time.UTC))
+	conf.Clock = fake
+	g := NewGameKeeper(conf)
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		g.Run()
+	}()
+	t.Cleanup(func() {
+		g.Stop()
+		<-stopped
+	})
+	fake.BlockUntil(keeperTickers)
+	return g, fake
+}
+
+// next returns the next response sent to the player.
+func next(t *testing.T, p *core.Messenger) string {
+	t.Helper()
+	select {
+	case resp := <-p.ReadResponses():
+		return resp.String()
+	case <-time.After(time.Second):
+		t.Fatal("no response was sent")
+		return ""
+	}
+}
+
+// nextOf skips the responses until one of the given type is sent.
+func nextOf(t *testing.T, p *core.Messenger, typ core.ResponseType) string {
+	t.Helper()
+	for {
+		if resp := next(t, p); strings.HasPrefix(resp, string(typ)+" ") {
+			return resp
+		}
+	}
+}
+
+// joinGame joins the server and a new game with the given options.
+func joinGame(t *testing.T, g *GameKeeper, fake *clock.Fake, name, game string) *core.Messenger {
+	t.Helper()
+	p := g.NewConnection(uid.NewTimeRand())
+	p.SendMessage(fmt.Sprintf("%s %s", core.CommandTypeJoinServer, name))
+	nextOf(t, p, core.ResponseTypeWelcome)
+
+	p.SendMessage(fmt.Sprintf("%s %s", core.CommandTypeJoinGame, game))
+	nextOf(t, p, core.ResponseTypeJoined)
+	fake.BlockUntil(keeperTickers + 1)
+	return p
+}
+
+func TestGameKeeper_ZombieReachesWall(t *testing.T) {
+	g, fake := startKeeper(t)
+	p := joinGame(t, g, fake, "alice", "g width=3 seed=7")
+	tick := core.DefaultGameOptions().Tick
+
+	// A zombie takes a single step at a time, so it's
+	// certain to reach the wall once it has walked 34 times.
+	for i := 0; i < 34; i++ {
+		fake.Advance(tick)
+		resp := next(t, p)
+		if strings.HasPrefix(resp, string(core.ResponseTypeWalk)+" ") {
+			continue
+		}
+		if !strings.HasPrefix(resp, string(core.ResponseTypeScore)+" ") {
+			t.Fatalf("got %q, want a WALK or SCORE", resp)
+		}
+		if got, want := nextOf(t, p, core.ResponseTypeFinish), core.NewResponseFinish(false).String(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		return
+	}
+	t.Fatal("zombie never reached the wall")
+}
+
+func TestGameKeeper_PlayersWin(t *testing.T) {
+	g, fake := startKeeper(t)
+	p := joinGame(t, g, fake, "alice", "g seed=7")
+
+	killZombie(t, fake, p, "alice")
+	nextOf(t, p, core.ResponseTypeScore)
+	if got, want := nextOf(t, p, core.ResponseTypeFinish), core.NewResponseFinish(true).String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGameKeeper_LeaverIsNotCredited(t *testing.T) {
+	g, fake := startKeeper(t)
+	alice := joinGame(t, g, fake, "alice", "g seed=7")
+	bob := joinGame(t, g, fake, "bob", "g")
+
+	// Alice is told first, bob is sent the reply afterwards.
+	bob.SendMessage(string(core.CommandTypeLeaveGame))
+	nextOf(t, alice, core.ResponseTypeLeft)
+	nextOf(t, bob, core.ResponseTypeLeft)
+
+	killZombie(t, fake, alice, "alice")
+	nextOf(t, alice, core.ResponseTypeFinish)
+
+	want := fmt.Sprintf("%s 1\n%s 1 alice 1 0 1 1.00", core.ResponseTypeLeaderboard, core.ResponseTypeRank)
+	if got := standings(t, alice); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// standings waits until the results of a finished game are
+// recorded, they are recorded after the players are told.
+func standings(t *testing.T, p *core.Messenger) string {
+	t.Helper()
+	empty := fmt.Sprintf("%s 0", core.ResponseTypeLeaderboard)
+	for i := 0; i < 100; i++ {
+		p.SendMessage(fmt.Sprintf("%s 10", core.CommandTypeLeaderboard))
+		if resp := nextOf(t, p, core.ResponseTypeLeaderboard); resp != empty {
+			return resp
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("results were never recorded")
+	return ""
+}
+
+// killZombie walks the only zombie of a classic
+// game once and shoots it until it dies.
+func killZombie(t *testing.T, fake *clock.Fake, p *core.Messenger, player string) {
+	t.Helper()
+	fake.Advance(core.DefaultGameOptions().Tick)
+	var name, typ string
+	var x, y int
+	walk := nextOf(t, p, core.ResponseTypeWalk)
+	if _, err := fmt.Sscanf(walk, "WALK %s %d %d %s", &name, &x, &y, &typ); err != nil {
+		t.Fatalf("parsing %q: %v", walk, err)
+	}
+
+	// The clock isn't advanced, so the zombie stands
+	// still while it's shot until it dies.
+	for hits := 1; hits <= zombieHitPoints; hits++ {
+		p.SendMessage(fmt.Sprintf("%s %d %d", core.CommandTypeShoot, x, y))
+		want := core.NewResponseBoom(player, name, typ, hits).String()
+		if got := next(t, p); got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestGameKeeper_SpectatorIsStreamedTo(t *testing.T) {
+	g, fake := startKeeper(t)
+	alice := joinGame(t, g, fake, "alice", "g tick=30s seed=7")
+
+	s := g.NewConnection(uid.NewTimeRand())
+	s.SendMessage(fmt.Sprintf("%s g", core.CommandTypeSpectate))
+	nextOf(t, s, core.ResponseTypeGameInfo)
+
+	// The spectator never sends anything, but is streamed to
+	// for a lot longer than a connection can stay idle.
+	for i := 0; i < 4; i++ {
+		fake.Advance(30 * time.Second)
+		walk := nextOf(t, alice, core.ResponseTypeWalk)
+		if got := nextOf(t, s, core.ResponseTypeWalk); got != walk {
+			t.Fatalf("got %q, want %q", got, walk)
+		}
+	}
+}
+
+func TestGameKeeper_ReapsEmptyGames(t *testing.T) {
+	g, fake := startKeeper(t)
+	alice := joinGame(t, g, fake, "alice", "busy tick=1m")
+	bob := joinGame(t, g, fake, "bob", "idle tick=1m")
+
+	bob.SendMessage(string(core.CommandTypeLeaveGame))
+	nextOf(t, bob, core.ResponseTypeLeft)
+	if got := gameInfo(t, bob, "idle"); !strings.HasPrefix(got, string(core.ResponseTypeGameInfo)+" ") {
+		t.Fatalf("got %q, want the empty game to be kept until ReapAfter", got)
+	}
+
+	fake.Advance(time.Minute)
+	nextOf(t, alice, core.ResponseTypeWalk)
+
+	// Every game is looked at when reaping, so once the empty
+	// one is gone the busy one has been left running.
+	for i := 0; ; i++ {
+		got := gameInfo(t, bob, "idle")
+		if got == core.NewResponseError(errNoGame).String() {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("got %q, want the empty game to be reaped", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := gameInfo(t, bob, "busy"); !strings.HasPrefix(got, string(core.ResponseTypeGameInfo)+" ") {
+		t.Errorf("got %q, want the game with a player to be kept", got)
+	}
+}
+
+func TestGameKeeper_Resume(t *testing.T) {
+	g, fake := startKeeperWith(t, Config{
+		ReapAfter:   time.Minute,
+		ResumeGrace: 30 * time.Second,
+	})
+	alice := g.NewConnection(uid.NewTimeRand())
+	alice.SendMessage(fmt.Sprintf("%s alice", core.CommandTypeJoinServer))
+	var token string
+	welcome := nextOf(t, alice, core.ResponseTypeWelcome)
+	if _, err := fmt.Sscanf(welcome, "WELCOME alice %s", &token); err != nil {
+		t.Fatalf("parsing %q: %v", welcome, err)
+	}
+	alice.SendMessage(fmt.Sprintf("%s g tick=10s", core.CommandTypeJoinGame))
+	nextOf(t, alice, core.ResponseTypeJoined)
+	fake.BlockUntil(keeperTickers + 1)
+	bob := joinGame(t, g, fake, "bob", "g")
+
+	// Bob is sent the walk after it was kept for alice.
+	alice.Disconnect()
+	fake.Advance(10 * time.Second)
+	walk := nextOf(t, bob, core.ResponseTypeWalk)
+
+	resumed := g.NewConnection(uid.NewTimeRand())
+	resumed.SendMessage(fmt.Sprintf("%s %s", core.CommandTypeResume, token))
+	want := core.NewResponseResumed("alice", 1).String() + "\n" + walk
+	if got := next(t, resumed); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	resumed.Disconnect()
+	bob.SendMessage(string(core.CommandTypeLeaveGame))
+	nextOf(t, bob, core.ResponseTypeLeft)
+	fake.Advance(30 * time.Second)
+
+	// The name is taken until the session expires.
+	late := g.NewConnection(uid.NewTimeRand())
+	for i := 0; ; i++ {
+		late.SendMessage(fmt.Sprintf("%s alice", core.CommandTypeJoinServer))
+		got := next(t, late)
+		if strings.HasPrefix(got, string(core.ResponseTypeWelcome)+" ") {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("got %q, want the session to expire", got)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	again := g.NewConnection(uid.NewTimeRand())
+	again.SendMessage(fmt.Sprintf("%s %s", core.CommandTypeResume, token))
+	if got, want := next(t, again), core.NewResponseError(errNoResume).String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGameKeeper_CompetitiveShootNeedsPlayers(t *testing.T) {
+	g, fake := startKeeper(t)
+	alice := joinGame(t, g, fake, "alice", "g mode=competitive")
+
+	alice.SendMessage(fmt.Sprintf("%s 0 0", core.CommandTypeShoot))
+	if got, want := next(t, alice), core.NewResponseError(errNotEnoughPlayers).String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	joinGame(t, g, fake, "bob", "g")
+	alice.SendMessage(fmt.Sprintf("%s 0 0", core.CommandTypeShoot))
+	if got := next(t, alice); strings.HasPrefix(got, string(core.ResponseTypeError)+" ") {
+		t.Errorf("got %q, want the shot to be taken", got)
+	}
+}
+
+func TestGameKeeper_Spectate(t *testing.T) {
+	g, fake := startKeeper(t)
+	alice := joinGame(t, g, fake, "alice", "g mode=competitive seed=7")
+	bob := joinGame(t, g, fake, "bob", "g")
+
+	s := g.NewConnection(uid.NewTimeRand())
+	s.SendMessage(fmt.Sprintf("%s g", core.CommandTypeSpectate))
+	nextOf(t, s, core.ResponseTypeGameInfo)
+	s.SendMessage(fmt.Sprintf("%s 0 0", core.CommandTypeShoot))
+	if got, want := next(t, s), core.NewResponseError(errSpectator).String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// Everyone is sent the same responses, so they are
+	// buffered to not block the keeper on the others.
+	ain, bin, sin := newInbox(t, alice), newInbox(t, bob), newInbox(t, s)
+	fake.Advance(core.DefaultGameOptions().Tick)
+	var name, typ string
+	var x, y int
+	walk := ain.nextOf(t, core.ResponseTypeWalk)
+	if _, err := fmt.Sscanf(walk, "WALK %s %d %d %s", &name, &x, &y, &typ); err != nil {
+		t.Fatalf("parsing %q: %v", walk, err)
+	}
+	if got := sin.nextOf(t, core.ResponseTypeWalk); got != walk {
+		t.Fatalf("got %q, want %q", got, walk)
+	}
+
+	for hits := 1; hits <= zombieHitPoints; hits++ {
+		alice.SendMessage(fmt.Sprintf("%s %d %d", core.CommandTypeShoot, x, y))
+		want := core.NewResponseBoom("alice", name, typ, hits).String()
+		if got := sin.nextOf(t, core.ResponseTypeBoom); got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+	want := core.NewResponseFinishWinner("alice").String()
+	if got := sin.nextOf(t, core.ResponseTypeFinish); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := bin.nextOf(t, core.ResponseTypeFinish); got != core.NewResponseFinish(false).String() {
+		t.Errorf("got %q, want bob to lose", got)
+	}
+}
+
+func TestGameKeeper_RecordsWhileGameIsRunning(t *testing.T) {
+	dir := t.TempDir()
+	g, fake := startKeeperWith(t, Config{ReapAfter: time.Minute, ReplayDir: dir})
+	alice := joinGame(t, g, fake, "alice", "g seed=7")
+	alice.SendMessage(fmt.Sprintf("%s 0 0", core.CommandTypeShoot))
+	next(t, alice)
+
+	// Replay is written off the keeper loop, but every
+	// event is flushed without waiting for the game to end.
+	path := filepath.Join(dir, replay.FileName("g", fake.Now()))
+	for i := 0; ; i++ {
+		if shot := recordedShot(t, path); shot != nil {
+			if *shot != (replay.Shot{Player: "alice", X: 0, Y: 0}) {
+				t.Errorf("got %+v, want the shot of alice", *shot)
+			}
+			return
+		}
+		if i == 100 {
+			t.Fatal("the shot wasn't recorded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// recordedShot returns the first shot of a replay, nil if there's none yet.
+func recordedShot(t *testing.T, path string) *replay.Shot {
+	t.Helper()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("opening the replay: %v", err)
+	}
+	defer f.Close()
+
+	events, err := replay.Read(f)
+	if err != nil {
+		t.Fatalf("reading the replay: %v", err)
+	}
+	for _, e := range events {
+		if e.Shot != nil {
+			return e.Shot
+		}
+	}
+	return nil
+}
+
+// gameInfo asks for the state of a game.
+func gameInfo(t *testing.T, p *core.Messenger, game string) string {
+	t.Helper()
+	p.SendMessage(fmt.Sprintf("%s %s", core.CommandTypeGameInfo, game))
+	return next(t, p)
+}
+
+// inbox buffers the responses sent to a player.
+type inbox chan string
+
+// newInbox starts buffering the responses sent to
+// the player, it stops once the test ends.
+func newInbox(t *testing.T, p *core.Messenger) inbox {
+	in := make(inbox, 100)
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	go func() {
+		for {
+			select {
+			case resp := <-p.ReadResponses():
+				in <- resp.String()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return in
+}
+
+// nextOf skips the buffered responses until one of the given type is sent.
+func (in inbox) nextOf(t *testing.T, typ core.ResponseType) string {
+	t.Helper()
+	for {
+		select {
+		case resp := <-in:
+			if strings.HasPrefix(resp, string(typ)+" ") {
+				return resp
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no %s was sent", typ)
+			return ""
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/tomasmik/winter-is-coming/core"
+	"github.com/tomasmik/winter-is-coming/metrics"
+)
+
+// KeeperStats is a snapshot of what the keeper
+// is doing and has done over its lifetime.
+type KeeperStats struct {
+	Players int
+	Games   int
+	Shots   int
+	Hits    int
+	Won     int
+	Lost    int
+	// Backlog is how many responses are waiting to be
+	// passed from the game instances to the keeper.
+	Backlog int
+}
+
+// Stats returns a snapshot of the keeper stats.
+func (g *GameKeeper) Stats() (KeeperStats, error) {
+	var stats KeeperStats
+	err := g.do(func() {
+		stats = KeeperStats{
+			Players: len(g.players),
+			Games:   len(g.instances),
+			Shots:   g.stats.shots,
+			Hits:    g.stats.hits,
+			Won:     g.stats.won,
+			Lost:    g.stats.lost,
+			Backlog: len(g.gmsg) + int(atomic.LoadInt64(&g.queued)),
+		}
+	})
+	return stats, err
+}
+
+// serverMetrics are the metrics the server measures itself,
+// the rest are taken from the keeper when they are scraped.
+type serverMetrics struct {
+	commands *metrics.CounterVec
+	// responses are counted by whether they are replies to a
+	// command or broadcasts, only the replies have a latency.
+	responses *metrics.CounterVec
+	latency   *metrics.Histogram
+}
+
+// Kinds of the written responses.
+const (
+	responseReply     = "reply"
+	responseBroadcast = "broadcast"
+)
+
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		commands:  metrics.NewCounterVec("command"),
+		responses: metrics.NewCounterVec("kind"),
+		latency:   metrics.NewHistogram(metrics.DefaultBuckets),
+	}
+}
+
+// written counts a response written to a connection, the
+// latency of a reply is measured from receiving its command.
+func (m *serverMetrics) written(resp core.Response) {
+	r, ok := resp.(*core.Reply)
+	if !ok || r.Request.Received.IsZero() {
+		m.responses.Inc(responseBroadcast)
+		return
+	}
+	m.responses.Inc(responseReply)
+	m.latency.Observe(time.Since(r.Request.Received).Seconds())
+}
+
+// Metrics returns a handler serving the metrics
+// in the Prometheus text exposition format.
+func (s *Server) Metrics() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, errMethod)
+			return
+		}
+		stats, err := s.gp.Stats()
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+
+		w.Header().Set("Content-Type", metrics.ContentType)
+		mw := metrics.NewWriter(w)
+		mw.Gauge("wic_connected_clients", "Clients connected to the server.", float64(s.cmanager.Count()))
+		mw.Gauge("wic_players", "Players who have joined the server, including the dropped ones.", float64(stats.Players))
+		mw.Gauge("wic_games", "Active game instances.", float64(stats.Games))
+		mw.Gauge("wic_response_backlog", "Responses waiting to be passed from the games to the players.", float64(stats.Backlog))
+		mw.Counter("wic_shots_total", "Shots fired by the players.", float64(stats.Shots))
+		mw.Counter("wic_hits_total", "Shots which hit a zombie.", float64(stats.Hits))
+		mw.Counter("wic_games_won_total", "Finished games won by the players.", float64(stats.Won))
+		mw.Counter("wic_games_lost_total", "Finished games won by the zombies.", float64(stats.Lost))
+		mw.CounterVec("wic_commands_total", "Commands received by type.", s.metrics.commands)
+		mw.CounterVec("wic_responses_total", "Responses written by kind, a reply to a command or a broadcast.", s.metrics.responses)
+		mw.Histogram("wic_response_seconds", "Time from receiving a command to writing the reply to it.", s.metrics.latency)
+		if err := mw.Err(); err != nil {
+			s.log.WithError(err).Error("writing metrics")
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tomasmik/winter-is-coming/core"
+)
+
+func TestServerMetrics_Written(t *testing.T) {
+	m := newServerMetrics()
+	resp := core.NewResponseError(errors.New("a"))
+
+	m.written(resp)
+	m.written(core.Request{}.Reply(resp))
+	m.written(core.Request{Received: time.Now()}.Reply(resp))
+
+	if got := m.responses.Value(responseBroadcast); got != 2 {
+		t.Errorf("broadcasts = %d, want 2", got)
+	}
+	if got := m.responses.Value(responseReply); got != 1 {
+		t.Errorf("replies = %d, want 1", got)
+	}
+	if got := m.latency.Count(); got != 1 {
+		t.Errorf("latency count = %d, want 1", got)
+	}
+}
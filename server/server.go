@@ -1,19 +1,64 @@
 package server
 
 import (
-	"bufio"
 	"errors"
+	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
 
 	"bitbucket.org/advbet/uid"
+	"github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
+	"github.com/tomasmik/winter-is-coming/auth"
+	"github.com/tomasmik/winter-is-coming/clock"
 	"github.com/tomasmik/winter-is-coming/core"
 )
 
+var (
+	errLateHandshake = errors.New("handshake should be the first message")
+	errAuthOff       = errors.New("authentication is disabled")
+	errRegister      = errors.New("could not register the name")
+)
+
+// invalidCommand is the type commands which
+// couldn't be parsed are counted under.
+const invalidCommand = "INVALID"
+
+// Config describes how the server manages its games.
+type Config struct {
+	// ReapAfter is how long a game without
+	// players is kept running before it's stopped.
+	ReapAfter time.Duration
+	// ResumeGrace is how long a player whose connection has
+	// dropped can resume his session, 0 disables resuming.
+	ResumeGrace time.Duration
+	// Leaderboard keeps the results of finished
+	// games, if it's nil they are kept in memory.
+	Leaderboard core.Leaderboard
+	// Auth decides whether players have to prove that they
+	// own their name, authentication is off by default.
+	Auth core.AuthMode
+	// Credentials keeps the secrets of registered
+	// names, if it's nil they are kept in memory.
+	Credentials core.Credentials
+	// ReplayDir is where every game is recorded,
+	// recording is disabled if it's empty.
+	ReplayDir string
+	// Defaults are the options of games created without
+	// any, DefaultGameOptions are used if they are nil.
+	Defaults *core.GameOptions
+	// Catalogue lists the zombie types games can spawn,
+	// DefaultCatalogue is used if it's empty.
+	Catalogue core.Catalogue
+	// Clock drives the games, the system clock is used if it's nil.
+	// Connection deadlines are always kept by the system clock.
+	Clock clock.Clock
+}
+
 // Server can be used to manage connections.
 // It wraps the listener allowing it to accept new connection
 // and keeps track of connected clients.
@@ -21,19 +66,30 @@ type Server struct {
 	l        net.Listener
 	cmanager *Cmanager
 	gp       *GameKeeper
+	auth     core.AuthMode
+	creds    core.Credentials
+	metrics  *serverMetrics
 
 	done chan struct{}
-	cwg  sync.WaitGroup
 	log  *logrus.Entry
 }
 
 // New creates a new tcp connection and returns
 // a new server object which can be used to manager that connection.
-func New(l net.Listener) *Server {
+func New(l net.Listener, conf Config) *Server {
+	if conf.Auth == "" {
+		conf.Auth = core.AuthModeOff
+	}
+	if conf.Credentials == nil {
+		conf.Credentials = auth.NewMemory()
+	}
 	return &Server{
 		l:        l,
-		gp:       NewGameKeeper(),
+		gp:       NewGameKeeper(conf),
+		auth:     conf.Auth,
+		creds:    conf.Credentials,
 		cmanager: NewCmanager(),
+		metrics:  newServerMetrics(),
 		done:     make(chan struct{}, 0),
 		log:      logrus.WithField("thread", "tcp-server"),
 	}
@@ -57,7 +113,7 @@ func (s *Server) Run() {
 		case <-s.done:
 			// Disconnets all users.
 			s.cmanager.UnregAll()
-			s.cwg.Wait()
+			s.cmanager.Wait()
 
 			// Stop all games
 			s.gp.Stop()
@@ -74,18 +130,13 @@ func (s *Server) Run() {
 				break
 			}
 
-			s.cmanager.Reg(c)
-			s.cwg.Add(1)
+			if !s.cmanager.Reg(c) {
+				break
+			}
 			go func(c net.Conn) {
-				defer func() {
-					// If client is stopped, it'll clean up itself.
-					if !s.stopped() {
-						s.cmanager.Unreg(c)
-					}
-				}()
-
-				defer s.cwg.Done()
-				s.handleConnection(c)
+				defer s.unreg(c)
+				defer s.cmanager.Done()
+				s.handleConnection(newTCPConn(c))
 			}(c)
 		}
 	}
@@ -93,34 +144,191 @@ func (s *Server) Run() {
 
 // listen will listen for any incoming messages and pass them along the send channel.
 // This func should block until the conection is closed or thread is stopped.
-func (s *Server) listen(c net.Conn, p *core.Messenger) {
-	for {
-		c.SetReadDeadline(time.Now().Add(time.Second * 60))
-		msg, err := bufio.NewReader(c).ReadString('\n')
+//
+// Encoding of the connection is picked by the first received message,
+// a client which starts with a JSON object or negotiates the JSON
+// capability will keep talking JSON. Commands can be tagged with
+// a request ID, which the replies to them are tagged with.
+func (s *Server) listen(c lineConn, p *core.Messenger) {
+	for first := true; ; first = false {
+		msg, err := c.ReadLine()
 		if err != nil {
 			if !errors.Is(err, io.EOF) && !s.stopped() {
 				s.log.WithError(err).Error("reading from a connection")
 			}
 			return
 		}
-		p.SendMessage(strings.TrimSpace(string(msg)))
+		msg = strings.TrimSpace(msg)
+		if first {
+			p.SetEncoding(core.DetectEncoding(msg))
+		}
+		req := core.Request{Received: time.Now()}
+		cmd, err := core.DecodeCommand(p.Encoding(), msg)
+		if err == nil {
+			req.ID, cmd, err = core.SplitRequestID(cmd)
+		}
+		if err != nil {
+			s.metrics.commands.Inc(invalidCommand)
+			p.Respond(req.Reply(core.NewResponseError(err)))
+			continue
+		}
+
+		typ, err := core.ParseCommandType(cmd)
+		if err != nil {
+			s.metrics.commands.Inc(invalidCommand)
+		} else {
+			s.metrics.commands.Inc(string(typ))
+		}
+
+		switch typ {
+		case core.CommandTypeHello:
+			s.handshake(p, cmd, req, first)
+		case core.CommandTypeRegister:
+			s.register(p, cmd, req)
+		case core.CommandTypeJoinServer:
+			s.joinServer(p, cmd, req)
+		default:
+			p.SendRequest(cmd, req)
+		}
+	}
+}
+
+// handshake negotiates the protocol version and capabilities
+// of a connection. Clients which don't start with a handshake
+// are left with the legacy protocol version.
+func (s *Server) handshake(p *core.Messenger, received string, req core.Request, first bool) {
+	if !first {
+		p.Respond(req.Reply(core.NewResponseError(errLateHandshake)))
+		return
+	}
+
+	cmd, err := core.ParseCommandHello(received)
+	if err != nil {
+		p.Respond(req.Reply(core.NewResponseError(err)))
+		return
+	}
+
+	version, caps := core.Negotiate(cmd)
+	p.SetProtocol(version, caps)
+	if p.HasCapability(core.CapabilityJSON) {
+		p.SetEncoding(core.EncodingJSON)
+	}
+	p.Respond(req.Reply(core.NewResponseHello(version, caps)))
+}
+
+// register protects a player name with a secret.
+func (s *Server) register(p *core.Messenger, received string, req core.Request) {
+	if s.auth == core.AuthModeOff {
+		p.Respond(req.Reply(core.NewResponseError(errAuthOff)))
+		return
+	}
+
+	cmd, err := core.ParseCommandRegister(received)
+	if err != nil {
+		p.Respond(req.Reply(core.NewResponseError(err)))
+		return
+	}
+
+	if err := s.creds.Register(cmd.Name, cmd.Secret); err != nil {
+		if !errors.Is(err, core.ErrNameRegistered) {
+			s.log.WithError(err).Error("registering a name")
+			err = errRegister
+		}
+		p.Respond(req.Reply(core.NewResponseError(err)))
+		return
 	}
+	p.Respond(req.Reply(core.NewResponseRegistered(cmd.Name)))
 }
 
-// write writes the received information to the connection.
-func (s *Server) write(c net.Conn, p *core.Messenger, stopped chan struct{}) {
+// joinServer checks the secret of the player before passing
+// the command along, so slow hashing doesn't block the keeper.
+func (s *Server) joinServer(p *core.Messenger, received string, req core.Request) {
+	cmd, err := core.ParseCommandJoinServer(received)
+	if err != nil {
+		p.Respond(req.Reply(core.NewResponseError(err)))
+		return
+	}
+
+	if err := core.Authenticate(s.auth, s.creds, cmd.Name, cmd.Secret); err != nil {
+		p.Respond(req.Reply(core.NewResponseError(err)))
+		return
+	}
+	p.SendRequest(fmt.Sprintf("%s %s", core.CommandTypeJoinServer, cmd.Name), req)
+}
+
+// write writes the received information to the connection,
+// encoded as the negotiated protocol version describes.
+func (s *Server) write(c lineConn, p *core.Messenger, stopped chan struct{}) {
 	// This is kinda hacky, but i guess ok for such a thing.
 	defer close(stopped)
 
 	for msg := range p.ReadResponses() {
-		c.SetWriteDeadline(time.Now().Add(time.Second * 5))
-		if _, err := c.Write([]byte(msg.String() + "\n")); err != nil {
-			s.log.WithError(err).Error("writing to a connection")
+		if b, ok := msg.(backlog); ok {
+			for _, resp := range b {
+				s.writeResponse(c, p, resp)
+			}
+			continue
+		}
+		s.writeResponse(c, p, msg)
+	}
+}
+
+// writeResponse writes a single response to the connection.
+func (s *Server) writeResponse(c lineConn, p *core.Messenger, resp core.Response) {
+	enc := resp
+	// Replies are tagged only if the client has asked for it.
+	if r, ok := resp.(*core.Reply); ok && !p.HasCapability(core.CapabilityRequestIDs) {
+		enc = r.Response
+	}
+	line, err := core.EncodeResponse(p.Encoding(), p.Version(), enc)
+	if errors.Is(err, core.ErrLegacySkipped) {
+		return
+	}
+	if err != nil {
+		s.log.WithError(err).Error("encoding a response")
+		return
+	}
+
+	if err := c.WriteLine(line); err != nil {
+		s.log.WithError(err).Error("writing to a connection")
+	}
+	s.metrics.written(resp)
+}
+
+// WebSocket returns a handler which upgrades the requests to WebSocket
+// connections. Every text frame carries a single command or response,
+// otherwise the clients are handled just like the TCP ones.
+func (s *Server) WebSocket() http.Handler {
+	upgrader := websocket.Upgrader{
+		// Browser clients are served from anywhere.
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrader has already replied with an error.
+			return
 		}
+
+		c := ws.UnderlyingConn()
+		if !s.cmanager.Reg(c) {
+			return
+		}
+		defer s.unreg(c)
+		defer s.cmanager.Done()
+		s.handleConnection(&wsConn{c: ws})
+	})
+}
+
+// unreg unregisters a client once it's handled.
+func (s *Server) unreg(c net.Conn) {
+	// If client is stopped, it'll clean up itself.
+	if !s.stopped() {
+		s.cmanager.Unreg(c)
 	}
 }
 
-func (s *Server) handleConnection(c net.Conn) {
+func (s *Server) handleConnection(c lineConn) {
 	p := s.gp.NewConnection(uid.NewTimeRand())
 
 	stopped := make(chan struct{})